@@ -0,0 +1,175 @@
+package mimetypes
+
+import (
+	"mime"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const DEFAULT_TYPE = "application/octet-stream"
+
+var builtinTypes = map[string]string{
+	".html":  "text/html; charset=utf-8",
+	".htm":   "text/html; charset=utf-8",
+	".css":   "text/css; charset=utf-8",
+	".csv":   "text/csv; charset=utf-8",
+	".txt":   "text/plain; charset=utf-8",
+	".md":    "text/markdown; charset=utf-8",
+	".js":    "text/javascript; charset=utf-8",
+	".mjs":   "text/javascript; charset=utf-8",
+	".json":  "application/json",
+	".map":   "application/json",
+	".xml":   "application/xml",
+	".yaml":  "application/yaml",
+	".yml":   "application/yaml",
+	".pdf":   "application/pdf",
+	".zip":   "application/zip",
+	".gz":    "application/gzip",
+	".tar":   "application/x-tar",
+	".wasm":  "application/wasm",
+	".png":   "image/png",
+	".jpg":   "image/jpeg",
+	".jpeg":  "image/jpeg",
+	".gif":   "image/gif",
+	".webp":  "image/webp",
+	".avif":  "image/avif",
+	".svg":   "image/svg+xml",
+	".ico":   "image/x-icon",
+	".mp3":   "audio/mpeg",
+	".wav":   "audio/wav",
+	".ogg":   "audio/ogg",
+	".mp4":   "video/mp4",
+	".webm":  "video/webm",
+	".woff":  "font/woff",
+	".woff2": "font/woff2",
+	".ttf":   "font/ttf",
+	".otf":   "font/otf",
+}
+
+var textTypes = map[string]bool{
+	"application/json":                  true,
+	"application/xml":                   true,
+	"application/x-www-form-urlencoded": true,
+	"application/javascript":            true,
+	"application/ecmascript":            true,
+	"application/x-javascript":          true,
+	"application/yaml":                  true,
+	"application/x-yaml":                true,
+	"application/toml":                  true,
+	"application/graphql":               true,
+	"application/sql":                   true,
+	"application/x-ndjson":              true,
+	"image/svg+xml":                     true,
+}
+
+var (
+	overridesMutex sync.RWMutex
+	overrides      = map[string]string{}
+)
+
+func Register(extension string, mediaType string) {
+	overridesMutex.Lock()
+	defer overridesMutex.Unlock()
+
+	overrides[normalizeExtension(extension)] = mediaType
+}
+
+func TypeByExtension(extension string) string {
+	extension = normalizeExtension(extension)
+
+	overridesMutex.RLock()
+	mediaType, ok := overrides[extension]
+	overridesMutex.RUnlock()
+
+	if ok {
+		return mediaType
+	}
+
+	if mediaType, ok := builtinTypes[extension]; ok {
+		return mediaType
+	}
+
+	if mediaType := mime.TypeByExtension(extension); mediaType != "" {
+		return mediaType
+	}
+
+	return DEFAULT_TYPE
+}
+
+func TypeByFilename(filename string) string {
+	index := strings.LastIndex(filename, ".")
+	if index == -1 {
+		return DEFAULT_TYPE
+	}
+
+	return TypeByExtension(filename[index:])
+}
+
+func ExtensionsByType(mediaType string) []string {
+	mediaType = Essence(mediaType)
+	found := map[string]bool{}
+
+	overridesMutex.RLock()
+	for extension, candidate := range overrides {
+		if Essence(candidate) == mediaType {
+			found[extension] = true
+		}
+	}
+	overridesMutex.RUnlock()
+
+	for extension, candidate := range builtinTypes {
+		if Essence(candidate) == mediaType {
+			found[extension] = true
+		}
+	}
+
+	extensions := []string{}
+	for extension := range found {
+		extensions = append(extensions, extension)
+	}
+	sort.Strings(extensions)
+
+	return extensions
+}
+
+// Essence strips parameters and casing, so "Text/HTML; charset=utf-8" becomes "text/html".
+func Essence(mediaType string) string {
+	if index := strings.Index(mediaType, ";"); index != -1 {
+		mediaType = mediaType[:index]
+	}
+
+	return strings.ToLower(strings.TrimSpace(mediaType))
+}
+
+func IsJson(mediaType string) bool {
+	essence := Essence(mediaType)
+	return essence == "application/json" || strings.HasSuffix(essence, "+json")
+}
+
+func IsXml(mediaType string) bool {
+	essence := Essence(mediaType)
+	return essence == "application/xml" || essence == "text/xml" || strings.HasSuffix(essence, "+xml")
+}
+
+func IsText(mediaType string) bool {
+	essence := Essence(mediaType)
+
+	if strings.HasPrefix(essence, "text/") || IsJson(essence) || IsXml(essence) {
+		return true
+	}
+
+	return textTypes[essence]
+}
+
+func IsBinary(mediaType string) bool {
+	return !IsText(mediaType)
+}
+
+func normalizeExtension(extension string) string {
+	extension = strings.ToLower(extension)
+	if !strings.HasPrefix(extension, ".") {
+		extension = "." + extension
+	}
+	return extension
+}
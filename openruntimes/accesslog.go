@@ -0,0 +1,86 @@
+package openruntimes
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// AccessLogOptions controls what the access log keeps of the query string:
+// OmitQuery drops it, RedactQuery masks the listed parameters. Parameters
+// registered with RegisterRedactedFields are always masked.
+type AccessLogOptions struct {
+	OmitQuery   bool
+	RedactQuery []string
+}
+
+func (o AccessLogOptions) query(queryString string) string {
+	if o.OmitQuery || queryString == "" {
+		return ""
+	}
+
+	pairs := strings.Split(queryString, "&")
+	for i, pair := range pairs {
+		rawKey, _, _ := strings.Cut(pair, "=")
+		key, err := url.QueryUnescape(rawKey)
+		if err != nil {
+			key = rawKey
+		}
+
+		redact := isRedactedField(key)
+		for _, name := range o.RedactQuery {
+			redact = redact || strings.EqualFold(name, key)
+		}
+
+		if redact {
+			pairs[i] = rawKey + "=" + REDACTED
+		}
+	}
+
+	return strings.Join(pairs, "&")
+}
+
+// AccessLog writes one info record per invocation with the method, path,
+// status, duration, bytes in and out, client IP, request id and whether the
+// invocation was a cold start, with the process's init duration when it was.
+func AccessLog(options AccessLogOptions) Middleware {
+	return func(next Handler) Handler {
+		return func(c *Context) Response {
+			start := time.Now()
+			response := next(c)
+
+			status := response.StatusCode
+			if status == 0 {
+				status = 200
+			}
+
+			fields := map[string]any{
+				"method":     strings.ToUpper(c.Req.Method),
+				"path":       c.Req.Path,
+				"status":     status,
+				"durationMs": float64(time.Since(start).Microseconds()) / 1000,
+				"bytesIn":    len(c.Req.BodyBinary()),
+				"clientIp":   c.Req.ClientIP(),
+				"requestId":  c.RequestID(),
+				"coldStart":  c.coldStart,
+			}
+
+			if c.coldStart {
+				fields["initDurationMs"] = float64(c.initDuration.Microseconds()) / 1000
+			}
+
+			if query := options.query(c.Req.QueryString); query != "" {
+				fields["query"] = query
+			}
+
+			if !response.IsStream() {
+				fields["bytesOut"] = len(response.Body)
+			}
+
+			c.logger.WriteWith(fields["method"].(string)+" "+c.Req.Path+" "+strconv.Itoa(status), fields, LOGGER_TYPE_INFO)
+
+			return response
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package openruntimes
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func accessLogRecord(t *testing.T, coldStart bool, url string) logRecord {
+	t.Helper()
+	t.Setenv("OPEN_RUNTIMES_LOG_FORMAT", LOGGER_FORMAT_JSON)
+
+	var logs bytes.Buffer
+	logger, err := NewLoggerWithOptions("enabled", "test", LoggerOptions{Dir: t.TempDir(), LogsWriter: &logs})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := NewContext(logger)
+	c.coldStart = coldStart
+	c.initDuration = 250 * time.Millisecond
+	c.Req.Method = "get"
+	c.Req.Path = "/items"
+	c.Req.SetQueryString(url)
+
+	AccessLog(AccessLogOptions{RedactQuery: []string{"secret"}})(func(c *Context) Response {
+		return c.Res.Text("ok", c.Res.WithStatusCode(201))
+	})(&c)
+	logger.End()
+
+	var record logRecord
+	if err := json.Unmarshal(logs.Bytes(), &record); err != nil {
+		t.Fatalf("access log %q is not one JSON record: %v", logs.String(), err)
+	}
+	return record
+}
+
+func TestAccessLog(t *testing.T) {
+	record := accessLogRecord(t, false, "page=2&secret=abc&token=xyz")
+
+	if record.Message != "GET /items 201" {
+		t.Errorf("message = %q", record.Message)
+	}
+	if query := record.Fields["query"]; query != "page=2&secret="+REDACTED+"&token="+REDACTED {
+		t.Errorf("query = %v, want secret and token redacted", query)
+	}
+	if record.Fields["bytesOut"] != float64(2) || record.Fields["status"] != float64(201) {
+		t.Errorf("fields = %v", record.Fields)
+	}
+	if record.Fields["coldStart"] != false {
+		t.Errorf("coldStart = %v, want false", record.Fields["coldStart"])
+	}
+	if _, ok := record.Fields["initDurationMs"]; ok {
+		t.Errorf("warm invocation logged initDurationMs")
+	}
+}
+
+func TestAccessLogColdStart(t *testing.T) {
+	record := accessLogRecord(t, true, "")
+
+	if record.Fields["coldStart"] != true {
+		t.Errorf("coldStart = %v, want true", record.Fields["coldStart"])
+	}
+	if record.Fields["initDurationMs"] != float64(250) {
+		t.Errorf("initDurationMs = %v, want 250", record.Fields["initDurationMs"])
+	}
+}
@@ -0,0 +1,124 @@
+package openruntimes
+
+import (
+	"io"
+	"sync"
+)
+
+const LOGGER_BUFFER_SIZE = 1024
+
+// asyncWriter queues writes in a fixed-size ring buffer and hands them to the
+// target from a background goroutine. A full buffer is flushed by the caller
+// instead of dropping lines, so async mode trades latency, never logs.
+type asyncWriter struct {
+	mutex    sync.Mutex
+	flushing sync.Mutex
+	target   io.Writer
+	entries  [][]byte
+	head     int
+	size     int
+	wake     chan struct{}
+	done     chan struct{}
+	closed   bool
+}
+
+func newAsyncWriter(target io.Writer, options LoggerOptions) *asyncWriter {
+	size := options.BufferSize
+	if size <= 0 {
+		size = LOGGER_BUFFER_SIZE
+	}
+
+	writer := &asyncWriter{
+		target:  target,
+		entries: make([][]byte, size),
+		wake:    make(chan struct{}, 1),
+		done:    make(chan struct{}),
+	}
+	go writer.run()
+
+	return writer
+}
+
+func (w *asyncWriter) run() {
+	for {
+		select {
+		case <-w.wake:
+			w.Flush()
+		case <-w.done:
+			return
+		}
+	}
+}
+
+func (w *asyncWriter) Write(p []byte) (int, error) {
+	entry := make([]byte, len(p))
+	copy(entry, p)
+
+	w.mutex.Lock()
+	for w.size == len(w.entries) && !w.closed {
+		w.mutex.Unlock()
+		w.Flush()
+		w.mutex.Lock()
+	}
+
+	if w.closed {
+		w.mutex.Unlock()
+		return w.target.Write(p)
+	}
+
+	w.entries[(w.head+w.size)%len(w.entries)] = entry
+	w.size++
+	w.mutex.Unlock()
+
+	select {
+	case w.wake <- struct{}{}:
+	default:
+	}
+
+	return len(p), nil
+}
+
+// Flush writes everything queued so far to the target, in order.
+func (w *asyncWriter) Flush() {
+	w.flushing.Lock()
+	defer w.flushing.Unlock()
+
+	w.mutex.Lock()
+	pending := make([][]byte, 0, w.size)
+	for i := 0; i < w.size; i++ {
+		index := (w.head + i) % len(w.entries)
+		pending = append(pending, w.entries[index])
+		w.entries[index] = nil
+	}
+	w.head = 0
+	w.size = 0
+	w.mutex.Unlock()
+
+	for _, entry := range pending {
+		w.target.Write(entry)
+	}
+}
+
+func (w *asyncWriter) Close() error {
+	w.mutex.Lock()
+	if w.closed {
+		w.mutex.Unlock()
+		return nil
+	}
+	w.closed = true
+	close(w.done)
+	w.mutex.Unlock()
+
+	w.Flush()
+	return nil
+}
+
+// Flush writes out anything async mode is still holding. End calls it, so
+// it is only needed to make logs visible while an execution is running.
+func (l *Logger) Flush() {
+	for _, writer := range []io.Writer{l.writerLogs, l.writerErrors} {
+		if buffered, ok := writer.(*asyncWriter); ok {
+			buffered.Flush()
+		}
+	}
+}
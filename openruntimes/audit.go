@@ -0,0 +1,58 @@
+package openruntimes
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+)
+
+type AuditEntry struct {
+	Timestamp    string `json:"timestamp"`
+	Action       string `json:"action"`
+	Actor        string `json:"actor"`
+	Target       string `json:"target"`
+	Outcome      string `json:"outcome"`
+	PreviousHash string `json:"previousHash"`
+	Hash         string `json:"hash,omitempty"`
+}
+
+// ComputeHash hashes the entry with its Hash field cleared, so each entry
+// commits to its predecessor through PreviousHash.
+func (e AuditEntry) ComputeHash() string {
+	e.Hash = ""
+	payload, _ := json.Marshal(e)
+	sum := sha256.Sum256(payload)
+	return hex.EncodeToString(sum[:])
+}
+
+func VerifyAuditChain(entries []AuditEntry) bool {
+	previousHash := ""
+
+	for _, entry := range entries {
+		if entry.PreviousHash != previousHash || entry.ComputeHash() != entry.Hash {
+			return false
+		}
+		previousHash = entry.Hash
+	}
+
+	return true
+}
+
+func (l *Logger) Audit(action string, actor string, target string, outcome string) {
+	defer l.lock()()
+
+	entry := AuditEntry{
+		Timestamp:    time.Now().UTC().Format(time.RFC3339Nano),
+		Action:       redactText(action),
+		Actor:        redactText(actor),
+		Target:       redactText(target),
+		Outcome:      redactText(outcome),
+		PreviousHash: l.lastAuditHash,
+	}
+	entry.Hash = entry.ComputeHash()
+	l.lastAuditHash = entry.Hash
+
+	line, _ := json.Marshal(entry)
+	l.write([]interface{}{string(line) + "\n"}, LOGGER_TYPE_AUDIT, false)
+}
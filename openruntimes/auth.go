@@ -0,0 +1,44 @@
+package openruntimes
+
+import (
+	"encoding/base64"
+	"strings"
+)
+
+func (r ContextRequest) BearerToken() (string, bool) {
+	scheme, credentials, ok := r.authorization()
+	if !ok || !strings.EqualFold(scheme, "Bearer") || credentials == "" {
+		return "", false
+	}
+
+	return credentials, true
+}
+
+func (r ContextRequest) BasicAuth() (user string, pass string, ok bool) {
+	scheme, credentials, ok := r.authorization()
+	if !ok || !strings.EqualFold(scheme, "Basic") {
+		return "", "", false
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(credentials)
+	if err != nil {
+		return "", "", false
+	}
+
+	user, pass, ok = strings.Cut(string(decoded), ":")
+	if !ok {
+		return "", "", false
+	}
+
+	return user, pass, true
+}
+
+func (r ContextRequest) authorization() (string, string, bool) {
+	header := strings.TrimSpace(r.Header("authorization"))
+	if header == "" {
+		return "", "", false
+	}
+
+	scheme, credentials, _ := strings.Cut(header, " ")
+	return scheme, strings.TrimSpace(credentials), true
+}
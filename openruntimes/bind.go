@@ -0,0 +1,243 @@
+package openruntimes
+
+import (
+	"errors"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/open-runtimes/types-for-go/v4/mimetypes"
+)
+
+// Bind fills the struct dst points to from the request. The body is decoded
+// by its content type with the registered codecs, so `json:"..."` and
+// `xml:"..."` tags apply; form bodies use `form:"..."` tags. Fields tagged
+// `query:"..."`, `header:"..."` or `param:"..."` (Router parameters) are then
+// set from those sources only; the body never reaches them, even through a
+// matching field name. Bodies without a registered codec, or structs without
+// a field left for the body, skip decoding. Conversion problems are
+// reported together as a *MultiError of FieldErrors; once binding succeeds,
+// the registered Validator runs and its ValidationErrors are returned.
+func (r ContextRequest) Bind(dst any) error {
+	target, err := bindTarget(dst)
+	if err != nil {
+		return err
+	}
+
+	problems := &MultiError{}
+
+	if len(r.BodyBinary()) > 0 {
+		contentType, _ := r.ContentType()
+
+		if mimetypes.Essence(contentType) == "application/x-www-form-urlencoded" {
+			form := ParseQueryString(r.BodyText())
+			bindTagged(target, "form", func(name string) []string { return form[name] }, problems)
+		} else if _, ok := LookupCodec(contentType); ok && hasUntagged(target, "query", "header", "param") {
+			restore := preserveTagged(target, "query", "header", "param")
+			if err := r.Decode(dst); err != nil {
+				problems.Add(FieldError{Message: err.Error()})
+			}
+			restore()
+		}
+	}
+
+	bindTagged(target, "query", r.QueryValues, problems)
+	bindTagged(target, "header", r.HeaderValues, problems)
+	bindTagged(target, "param", func(name string) []string {
+		if value, ok := r.params[name]; ok {
+			return []string{value}
+		}
+		return nil
+	}, problems)
+
+	if err := problems.ErrorOrNil(); err != nil {
+		return err
+	}
+
+	return currentValidator().Validate(dst)
+}
+
+// BindQuery fills the fields of the struct dst points to that carry a
+// `query:"..."` tag, then runs the registered Validator. Slices take repeated
+// parameters, comma-separated values or both ("?tag=a,b&tag=c"); time.Time
+// accepts RFC 3339, dates and Unix seconds.
+func (r ContextRequest) BindQuery(dst any) error {
+	target, err := bindTarget(dst)
+	if err != nil {
+		return err
+	}
+
+	problems := &MultiError{}
+	bindTagged(target, "query", r.QueryValues, problems)
+
+	if err := problems.ErrorOrNil(); err != nil {
+		return err
+	}
+
+	return currentValidator().Validate(dst)
+}
+
+// BindHeaders fills the fields of the struct dst points to that carry a
+// `header:"..."` tag, matching names case-insensitively, then runs the
+// registered Validator. A tag such as `header:"X-Api-Key,required"` reports
+// the header when it is missing or empty.
+func (r ContextRequest) BindHeaders(dst any) error {
+	target, err := bindTarget(dst)
+	if err != nil {
+		return err
+	}
+
+	problems := &MultiError{}
+	bindTagged(target, "header", r.HeaderValues, problems)
+
+	if err := problems.ErrorOrNil(); err != nil {
+		return err
+	}
+
+	return currentValidator().Validate(dst)
+}
+
+func bindTarget(dst any) (reflect.Value, error) {
+	target := reflect.ValueOf(dst)
+	if target.Kind() != reflect.Pointer || target.IsNil() || target.Elem().Kind() != reflect.Struct {
+		return reflect.Value{}, errors.New("bind expects a pointer to a struct")
+	}
+	return target.Elem(), nil
+}
+
+// hasUntagged reports whether target has an exported field carrying none of
+// tags, one the body could fill, descending into embedded structs.
+func hasUntagged(target reflect.Value, tags ...string) bool {
+	for i := 0; i < target.NumField(); i++ {
+		field := target.Type().Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		tagged := false
+		for _, tag := range tags {
+			if name, ok := field.Tag.Lookup(tag); ok && name != "-" {
+				tagged = true
+			}
+		}
+		if tagged {
+			continue
+		}
+
+		if !field.Anonymous || field.Type.Kind() != reflect.Struct || hasUntagged(target.Field(i), tags...) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// preserveTagged saves and clears the fields carrying any of tags, so
+// decoding the body cannot set them or write through their pointers and
+// maps, and returns a function putting them back.
+func preserveTagged(target reflect.Value, tags ...string) func() {
+	fields := []reflect.Value{}
+	saved := []reflect.Value{}
+
+	var collect func(reflect.Value)
+	collect = func(value reflect.Value) {
+		for i := 0; i < value.NumField(); i++ {
+			field := value.Type().Field(i)
+			if !field.IsExported() {
+				continue
+			}
+
+			claimed := false
+			for _, tag := range tags {
+				if name, ok := field.Tag.Lookup(tag); ok && name != "-" {
+					claimed = true
+				}
+			}
+
+			if claimed {
+				copied := reflect.New(field.Type).Elem()
+				copied.Set(value.Field(i))
+				value.Field(i).Set(reflect.Zero(field.Type))
+				fields = append(fields, value.Field(i))
+				saved = append(saved, copied)
+			} else if field.Anonymous && field.Type.Kind() == reflect.Struct {
+				collect(value.Field(i))
+			}
+		}
+	}
+	collect(target)
+
+	return func() {
+		for i, field := range fields {
+			field.Set(saved[i])
+		}
+	}
+}
+
+// bindTagged sets every field carrying tag from lookup, descending into
+// embedded structs. Slices collect every value, each split on commas; other
+// fields are converted from the first value by assignString. The "required"
+// option reports a source that is missing or empty.
+func bindTagged(target reflect.Value, tag string, lookup func(string) []string, problems *MultiError) {
+	for i := 0; i < target.NumField(); i++ {
+		field := target.Type().Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, ok := field.Tag.Lookup(tag)
+		if !ok {
+			if field.Anonymous && field.Type.Kind() == reflect.Struct {
+				bindTagged(target.Field(i), tag, lookup, problems)
+			}
+			continue
+		}
+
+		name, options, _ := strings.Cut(name, ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		values := lookup(name)
+		if len(values) == 0 || (len(values) == 1 && strings.TrimSpace(values[0]) == "") {
+			if slices.Contains(strings.Split(options, ","), "required") {
+				problems.Add(FieldError{Field: name, Message: "is required"})
+			}
+			if len(values) == 0 {
+				continue
+			}
+		}
+
+		if err := assignValues(target.Field(i), values); err != nil {
+			problems.Add(FieldError{Field: name, Message: err.Error()})
+		}
+	}
+}
+
+func assignValues(value reflect.Value, values []string) error {
+	if value.Kind() != reflect.Slice || value.Type().Elem().Kind() == reflect.Uint8 {
+		return assignString(value, values[0])
+	}
+
+	parts := []string{}
+	for _, raw := range values {
+		for _, part := range strings.Split(raw, ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				parts = append(parts, part)
+			}
+		}
+	}
+
+	slice := reflect.MakeSlice(value.Type(), len(parts), len(parts))
+	for i, part := range parts {
+		if err := assignString(slice.Index(i), part); err != nil {
+			return err
+		}
+	}
+	value.Set(slice)
+
+	return nil
+}
@@ -0,0 +1,273 @@
+package openruntimes
+
+import (
+	"errors"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type bindOrder struct {
+	Name     string   `json:"name"`
+	Quantity int      `json:"quantity"`
+	Tenant   string   `header:"x-tenant"`
+	Page     int      `query:"page"`
+	Tags     []string `query:"tag"`
+	Id       string   `param:"id"`
+}
+
+func newBindRequest(contentType string, body string) ContextRequest {
+	req := ContextRequest{Headers: map[string]string{"content-type": contentType}}
+	req.SetBodyBinary([]byte(body))
+	return req
+}
+
+func TestBindAllSources(t *testing.T) {
+	req := newBindRequest("application/json", `{"name":"widget","quantity":3}`)
+	req.SetHeader("x-tenant", "acme")
+	req.SetQueryString("page=2&tag=a,b&tag=c")
+	req.params = map[string]string{"id": "42"}
+
+	var order bindOrder
+	if err := req.Bind(&order); err != nil {
+		t.Fatalf("Bind() error = %v", err)
+	}
+
+	if order.Name != "widget" || order.Quantity != 3 || order.Tenant != "acme" || order.Page != 2 || order.Id != "42" {
+		t.Errorf("Bind() = %+v", order)
+	}
+	if len(order.Tags) != 3 || order.Tags[2] != "c" {
+		t.Errorf("Tags = %q, want [a b c]", order.Tags)
+	}
+}
+
+func TestBindBodyCannotSetTaggedFields(t *testing.T) {
+	req := newBindRequest("application/json", `{"name":"widget","Tenant":"evil","Page":9,"Id":"1"}`)
+
+	order := bindOrder{Page: 1}
+	if err := req.Bind(&order); err != nil {
+		t.Fatalf("Bind() error = %v", err)
+	}
+
+	if order.Tenant != "" || order.Page != 1 || order.Id != "" {
+		t.Errorf("body reached tagged fields: %+v", order)
+	}
+}
+
+func TestBindBodyCannotWriteThroughTaggedPointers(t *testing.T) {
+	type target struct {
+		Tenant *string `header:"x-tenant"`
+	}
+
+	tenant := "acme"
+	value := target{Tenant: &tenant}
+	req := newBindRequest("application/json", `{"Tenant":"evil"}`)
+
+	if err := req.Bind(&value); err != nil {
+		t.Fatalf("Bind() error = %v", err)
+	}
+	if tenant != "acme" || *value.Tenant != "acme" {
+		t.Errorf("Tenant = %q, want %q", *value.Tenant, "acme")
+	}
+}
+
+func TestBindForm(t *testing.T) {
+	type login struct {
+		User     string `form:"user"`
+		Remember bool   `form:"remember"`
+	}
+
+	req := newBindRequest("application/x-www-form-urlencoded", "user=ana&remember=true")
+
+	var value login
+	if err := req.Bind(&value); err != nil {
+		t.Fatalf("Bind() error = %v", err)
+	}
+	if value.User != "ana" || !value.Remember {
+		t.Errorf("Bind() = %+v", value)
+	}
+}
+
+func TestBindReportsEveryConversionProblem(t *testing.T) {
+	req := newBindRequest("", "")
+	req.SetQueryString("page=x&tag=a")
+
+	type filters struct {
+		Page  int   `query:"page"`
+		Limit int   `query:"limit,required"`
+		Tag   []int `query:"tag"`
+	}
+
+	err := req.Bind(&filters{})
+
+	var multi *MultiError
+	if !errors.As(err, &multi) || multi.Len() != 3 {
+		t.Fatalf("Bind() error = %v, want 3 problems", err)
+	}
+}
+
+func TestBindRejectsNonStruct(t *testing.T) {
+	var value int
+	if err := (ContextRequest{}).Bind(&value); err == nil {
+		t.Fatal("Bind() accepted a pointer to an int")
+	}
+	if err := (ContextRequest{}).Bind(bindOrder{}); err == nil {
+		t.Fatal("Bind() accepted a struct value")
+	}
+}
+
+func TestBindQuery(t *testing.T) {
+	type filters struct {
+		Page   int           `query:"page" validate:"min=1"`
+		Active *bool         `query:"active"`
+		Since  time.Time     `query:"since"`
+		Ids    []int         `query:"id"`
+		Tags   []string      `query:"tag"`
+		Ttl    time.Duration `query:"ttl"`
+		Body   string        `json:"body"`
+	}
+
+	req := newBindRequest("application/json", `{"body":"ignored"}`)
+	req.SetQueryString("page=2&active=true&since=2024-01-02&id=1,2&id=3&tag=a&tag=b,c&ttl=5s")
+
+	var value filters
+	if err := req.BindQuery(&value); err != nil {
+		t.Fatalf("BindQuery() error = %v", err)
+	}
+
+	if value.Page != 2 || value.Active == nil || !*value.Active || value.Ttl != 5*time.Second || value.Body != "" {
+		t.Errorf("BindQuery() = %+v", value)
+	}
+	if !value.Since.Equal(time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Since = %v", value.Since)
+	}
+	if !reflect.DeepEqual(value.Ids, []int{1, 2, 3}) || !reflect.DeepEqual(value.Tags, []string{"a", "b", "c"}) {
+		t.Errorf("Ids = %v, Tags = %q", value.Ids, value.Tags)
+	}
+}
+
+func TestBindQueryTimeFormats(t *testing.T) {
+	tests := map[string]time.Time{
+		"2024-01-02T03:04:05Z":      time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		"2024-01-02T03:04:05+02:00": time.Date(2024, 1, 2, 1, 4, 5, 0, time.UTC),
+		"2024-01-02":                time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+		"1700000000":                time.Unix(1700000000, 0),
+	}
+
+	for raw, want := range tests {
+		t.Run(raw, func(t *testing.T) {
+			var value struct {
+				Since time.Time `query:"since"`
+			}
+
+			req := ContextRequest{}
+			req.SetQueryString("since=" + url.QueryEscape(raw))
+
+			if err := req.BindQuery(&value); err != nil {
+				t.Fatalf("BindQuery() error = %v", err)
+			}
+			if !value.Since.Equal(want) {
+				t.Errorf("Since = %v, want %v", value.Since, want)
+			}
+		})
+	}
+}
+
+func TestBindQueryProblems(t *testing.T) {
+	type filters struct {
+		Page  int       `query:"page" validate:"min=1"`
+		Since time.Time `query:"since"`
+		Ids   []int     `query:"id"`
+	}
+
+	req := ContextRequest{}
+	req.SetQueryString("page=x&since=yesterday&id=1,y")
+
+	var multi *MultiError
+	if err := req.BindQuery(&filters{}); !errors.As(err, &multi) || multi.Len() != 3 {
+		t.Fatalf("BindQuery() error = %v, want 3 conversion problems", err)
+	}
+
+	req.SetQueryString("page=0")
+	got := validationFields(t, req.BindQuery(&filters{}))
+	if got["page"] != "must be at least 1" {
+		t.Fatalf("BindQuery() = %v, want page validated", got)
+	}
+}
+
+func TestBindHeaders(t *testing.T) {
+	type auth struct {
+		ApiKey string   `header:"X-Api-Key,required"`
+		Scopes []string `header:"X-Scopes"`
+		Retry  int      `header:"retry-after"`
+	}
+
+	tests := []struct {
+		name    string
+		headers map[string]string
+		want    auth
+		missing bool
+	}{
+		{
+			name:    "case-insensitive names",
+			headers: map[string]string{"x-api-key": "KEY", "x-scopes": "read, write", "retry-after": "3"},
+			want:    auth{ApiKey: "KEY", Scopes: []string{"read", "write"}, Retry: 3},
+		},
+		{
+			name:    "missing required header",
+			headers: map[string]string{"x-scopes": "read"},
+			missing: true,
+		},
+		{
+			name:    "blank required header",
+			headers: map[string]string{"x-api-key": "  "},
+			missing: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := ContextRequest{Headers: test.headers}
+
+			var value auth
+			err := req.BindHeaders(&value)
+
+			if test.missing {
+				var multi *MultiError
+				if !errors.As(err, &multi) || !strings.Contains(err.Error(), "X-Api-Key") {
+					t.Fatalf("BindHeaders() error = %v, want X-Api-Key required", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("BindHeaders() error = %v", err)
+			}
+			if !reflect.DeepEqual(value, test.want) {
+				t.Errorf("BindHeaders() = %+v, want %+v", value, test.want)
+			}
+		})
+	}
+}
+
+func TestBindSkipsBodyWithoutCodecOrFields(t *testing.T) {
+	type search struct {
+		Term string `query:"q"`
+	}
+
+	for _, contentType := range []string{"text/plain", "application/json"} {
+		t.Run(contentType, func(t *testing.T) {
+			req := newBindRequest(contentType, "not a decodable body")
+			req.SetQueryString("q=widget")
+
+			var value search
+			if err := req.Bind(&value); err != nil {
+				t.Fatalf("Bind() error = %v", err)
+			}
+			if value.Term != "widget" {
+				t.Errorf("Term = %q, want %q", value.Term, "widget")
+			}
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package openruntimes
+
+// ResponseBuilder is a chainable alternative to passing ResponseOption values.
+// Its state can be inspected before a terminal method builds the Response.
+type ResponseBuilder struct {
+	res        ContextResponse
+	statusCode int
+	headers    map[string]string
+	options    []ResponseOption
+}
+
+func (r ContextResponse) Build() *ResponseBuilder {
+	return &ResponseBuilder{
+		res:     r,
+		headers: map[string]string{},
+	}
+}
+
+func (b *ResponseBuilder) Status(statusCode int) *ResponseBuilder {
+	b.statusCode = statusCode
+	return b
+}
+
+func (b *ResponseBuilder) Header(key string, value string) *ResponseBuilder {
+	headerSet(b.headers, key, value)
+	return b
+}
+
+func (b *ResponseBuilder) AddHeader(key string, value string) *ResponseBuilder {
+	b.options = append(b.options, b.res.AddHeader(key, value))
+	return b
+}
+
+func (b *ResponseBuilder) Cookie(cookie Cookie) *ResponseBuilder {
+	b.options = append(b.options, b.res.WithCookie(cookie))
+	return b
+}
+
+func (b *ResponseBuilder) With(optionalSetters ...ResponseOption) *ResponseBuilder {
+	b.options = append(b.options, optionalSetters...)
+	return b
+}
+
+func (b *ResponseBuilder) StatusCode() int {
+	return b.statusCode
+}
+
+func (b *ResponseBuilder) Headers() map[string]string {
+	return cloneStringMap(b.headers)
+}
+
+func (b *ResponseBuilder) Options() []ResponseOption {
+	optionalSetters := []ResponseOption{}
+
+	if len(b.headers) > 0 {
+		optionalSetters = append(optionalSetters, b.res.WithHeaders(cloneStringMap(b.headers)))
+	}
+
+	optionalSetters = append(optionalSetters, b.options...)
+
+	if b.statusCode != 0 {
+		optionalSetters = append(optionalSetters, b.res.WithStatusCode(b.statusCode))
+	}
+
+	return optionalSetters
+}
+
+func (b *ResponseBuilder) Binary(bytes []byte) Response {
+	return b.res.Binary(bytes, b.Options()...)
+}
+
+func (b *ResponseBuilder) Text(body string) Response {
+	return b.res.Text(body, b.Options()...)
+}
+
+func (b *ResponseBuilder) Send(body string) Response {
+	return b.res.Send(body, b.Options()...)
+}
+
+func (b *ResponseBuilder) Json(v interface{}) Response {
+	return b.res.Json(v, b.Options()...)
+}
+
+func (b *ResponseBuilder) Html(body string) Response {
+	return b.res.Html(body, b.Options()...)
+}
+
+func (b *ResponseBuilder) Redirect(url string) Response {
+	return b.res.Redirect(url, b.Options()...)
+}
+
+func (b *ResponseBuilder) Empty() Response {
+	if b.statusCode == 0 {
+		b.statusCode = 204
+	}
+	return b.res.Binary([]byte{}, b.Options()...)
+}
@@ -0,0 +1,141 @@
+package openruntimes
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+type CacheControl struct {
+	maxAge               *time.Duration
+	sharedMaxAge         *time.Duration
+	staleWhileRevalidate *time.Duration
+	staleIfError         *time.Duration
+	public               bool
+	private              bool
+	noCache              bool
+	noStore              bool
+	noTransform          bool
+	mustRevalidate       bool
+	proxyRevalidate      bool
+	immutable            bool
+}
+
+type CacheDirective func(*CacheControl)
+
+func MaxAge(d time.Duration) CacheDirective {
+	return func(c *CacheControl) { c.maxAge = &d }
+}
+
+func SMaxAge(d time.Duration) CacheDirective {
+	return func(c *CacheControl) { c.sharedMaxAge = &d }
+}
+
+func StaleWhileRevalidate(d time.Duration) CacheDirective {
+	return func(c *CacheControl) { c.staleWhileRevalidate = &d }
+}
+
+func StaleIfError(d time.Duration) CacheDirective {
+	return func(c *CacheControl) { c.staleIfError = &d }
+}
+
+func Public() CacheDirective {
+	return func(c *CacheControl) { c.public = true }
+}
+
+func Private() CacheDirective {
+	return func(c *CacheControl) { c.private = true }
+}
+
+func NoCache() CacheDirective {
+	return func(c *CacheControl) { c.noCache = true }
+}
+
+func NoStore() CacheDirective {
+	return func(c *CacheControl) { c.noStore = true }
+}
+
+func NoTransform() CacheDirective {
+	return func(c *CacheControl) { c.noTransform = true }
+}
+
+func MustRevalidate() CacheDirective {
+	return func(c *CacheControl) { c.mustRevalidate = true }
+}
+
+func ProxyRevalidate() CacheDirective {
+	return func(c *CacheControl) { c.proxyRevalidate = true }
+}
+
+func Immutable() CacheDirective {
+	return func(c *CacheControl) { c.immutable = true }
+}
+
+func NewCacheControl(directives ...CacheDirective) CacheControl {
+	cacheControl := CacheControl{}
+	for _, directive := range directives {
+		directive(&cacheControl)
+	}
+	return cacheControl
+}
+
+// String renders directives in a fixed order. Private wins over Public, and
+// NoStore drops everything else since nothing may be cached anyway.
+func (c CacheControl) String() string {
+	if c.noStore {
+		return "no-store"
+	}
+
+	directives := []string{}
+
+	if c.private {
+		directives = append(directives, "private")
+	} else if c.public {
+		directives = append(directives, "public")
+	}
+
+	if c.noCache {
+		directives = append(directives, "no-cache")
+	}
+	if c.noTransform {
+		directives = append(directives, "no-transform")
+	}
+	if c.maxAge != nil {
+		directives = append(directives, "max-age="+cacheSeconds(*c.maxAge))
+	}
+	if c.sharedMaxAge != nil && !c.private {
+		directives = append(directives, "s-maxage="+cacheSeconds(*c.sharedMaxAge))
+	}
+	if c.mustRevalidate {
+		directives = append(directives, "must-revalidate")
+	}
+	if c.proxyRevalidate && !c.private {
+		directives = append(directives, "proxy-revalidate")
+	}
+	if c.immutable {
+		directives = append(directives, "immutable")
+	}
+	if c.staleWhileRevalidate != nil {
+		directives = append(directives, "stale-while-revalidate="+cacheSeconds(*c.staleWhileRevalidate))
+	}
+	if c.staleIfError != nil {
+		directives = append(directives, "stale-if-error="+cacheSeconds(*c.staleIfError))
+	}
+
+	return strings.Join(directives, ", ")
+}
+
+func cacheSeconds(d time.Duration) string {
+	if d < 0 {
+		d = 0
+	}
+	return strconv.FormatInt(int64(d/time.Second), 10)
+}
+
+func (r ContextResponse) WithCache(directives ...CacheDirective) ResponseOption {
+	return func(o *Response) {
+		o.mergeHeaders(map[string]string{
+			"cache-control": NewCacheControl(directives...).String(),
+		})
+	}
+}
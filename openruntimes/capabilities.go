@@ -0,0 +1,61 @@
+package openruntimes
+
+import (
+	"sort"
+	"strings"
+)
+
+type RouteMetadata struct {
+	Methods     []string
+	AcceptPost  []string
+	AcceptPatch []string
+	Examples    map[string]Response
+}
+
+func (m RouteMetadata) Allow() string {
+	seen := map[string]bool{"OPTIONS": true}
+	methods := []string{}
+
+	for _, method := range m.Methods {
+		method = strings.ToUpper(method)
+		if !seen[method] {
+			seen[method] = true
+			methods = append(methods, method)
+		}
+	}
+
+	if seen["GET"] && !seen["HEAD"] {
+		methods = append(methods, "HEAD")
+	}
+
+	sort.Strings(methods)
+	return strings.Join(append(methods, "OPTIONS"), ", ")
+}
+
+func (r ContextResponse) Capabilities(metadata RouteMetadata, optionalSetters ...ResponseOption) Response {
+	headers := map[string]string{
+		"allow": metadata.Allow(),
+	}
+
+	if len(metadata.AcceptPost) > 0 {
+		headers["accept-post"] = strings.Join(metadata.AcceptPost, ", ")
+	}
+
+	if len(metadata.AcceptPatch) > 0 {
+		headers["accept-patch"] = strings.Join(metadata.AcceptPatch, ", ")
+	}
+
+	optionalSetters = append([]ResponseOption{r.WithStatusCode(204), r.WithHeaders(headers)}, optionalSetters...)
+	return r.Binary([]byte{}, optionalSetters...)
+}
+
+func AdvertiseCapabilities(metadata RouteMetadata) Middleware {
+	return func(next Handler) Handler {
+		return func(c *Context) Response {
+			if strings.EqualFold(c.Req.Method, "OPTIONS") {
+				return c.Res.Capabilities(metadata)
+			}
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package openruntimes
+
+import (
+	"math/rand"
+	"os"
+	"strconv"
+	"time"
+)
+
+type ChaosOptions struct {
+	Latency         time.Duration
+	LatencyJitter   time.Duration
+	ErrorRate       float64
+	ErrorStatusCode int
+	TruncateRate    float64
+}
+
+func IsChaosMode() bool {
+	status := os.Getenv("OPEN_RUNTIMES_CHAOS")
+	return status == "enabled" || status == "true"
+}
+
+// Chaos injects latency, failures and truncated bodies while OPEN_RUNTIMES_CHAOS
+// is enabled. Rates are probabilities between 0 and 1.
+func Chaos(options ChaosOptions) Middleware {
+	if options.ErrorStatusCode == 0 {
+		options.ErrorStatusCode = 503
+	}
+
+	return func(next Handler) Handler {
+		return func(c *Context) Response {
+			if !IsChaosMode() {
+				return next(c)
+			}
+
+			delay := options.Latency
+			if options.LatencyJitter > 0 {
+				delay += time.Duration(rand.Int63n(int64(options.LatencyJitter)))
+			}
+			if delay > 0 {
+				time.Sleep(delay)
+			}
+
+			if options.ErrorRate > 0 && rand.Float64() < options.ErrorRate {
+				c.Error("Chaos: injected " + strconv.Itoa(options.ErrorStatusCode) + " response.")
+				return c.Res.Text("Injected failure", c.Res.WithStatusCode(options.ErrorStatusCode))
+			}
+
+			response := next(c)
+
+			if options.TruncateRate > 0 && len(response.Body) > 1 && rand.Float64() < options.TruncateRate {
+				c.Error("Chaos: truncated response body.")
+				response.Body = response.Body[:rand.Intn(len(response.Body))]
+			}
+
+			return response
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package openruntimes
+
+// With returns a child logger that shares the parent's streams and adds
+// fields to every record it writes. Fields given per call take precedence.
+func (l *Logger) With(fields map[string]any) *Logger {
+	child := *l
+
+	child.fields = make(map[string]any, len(l.fields)+len(fields))
+	for key, value := range l.fields {
+		child.fields[key] = value
+	}
+	for key, value := range fields {
+		child.fields[key] = value
+	}
+
+	return &child
+}
+
+func (c *Context) LoggerWith(fields map[string]any) *Logger {
+	return c.logger.With(fields)
+}
+
+func (l *Logger) Log(messages ...interface{}) {
+	l.writeLine(messages, LOGGER_TYPE_LOG)
+}
+
+func (l *Logger) Error(messages ...interface{}) {
+	l.writeLine(messages, LOGGER_TYPE_ERROR)
+}
+
+func (l *Logger) Debug(messages ...interface{}) {
+	l.writeLine(messages, LOGGER_TYPE_DEBUG)
+}
+
+func (l *Logger) Info(messages ...interface{}) {
+	l.writeLine(messages, LOGGER_TYPE_INFO)
+}
+
+func (l *Logger) Warn(messages ...interface{}) {
+	l.writeLine(messages, LOGGER_TYPE_WARN)
+}
+
+func (l *Logger) boundFields(fields map[string]any) map[string]any {
+	if len(l.fields) == 0 {
+		return fields
+	}
+
+	merged := make(map[string]any, len(l.fields)+len(fields))
+	for key, value := range l.fields {
+		merged[key] = value
+	}
+	for key, value := range fields {
+		merged[key] = value
+	}
+
+	return merged
+}
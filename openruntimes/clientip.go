@@ -0,0 +1,135 @@
+package openruntimes
+
+import (
+	"errors"
+	"net"
+	"strings"
+)
+
+// ProxyPolicy describes the proxies in front of the function. Header names
+// the one forwarding header they set: "x-forwarded-for" when empty,
+// "forwarded" for RFC 7239 or "x-real-ip" for a proxy that sends only the
+// client's address. The others are ignored, as clients can send them.
+type ProxyPolicy struct {
+	TrustedProxies []*net.IPNet
+	TrustPrivate   bool
+	Header         string
+}
+
+var DefaultProxyPolicy = ProxyPolicy{
+	TrustPrivate: true,
+}
+
+func NewProxyPolicy(cidrs ...string) (ProxyPolicy, error) {
+	policy := ProxyPolicy{}
+
+	for _, cidr := range cidrs {
+		if !strings.Contains(cidr, "/") {
+			if strings.Contains(cidr, ":") {
+				cidr += "/128"
+			} else {
+				cidr += "/32"
+			}
+		}
+
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return ProxyPolicy{}, errors.New("invalid trusted proxy: " + cidr)
+		}
+
+		policy.TrustedProxies = append(policy.TrustedProxies, network)
+	}
+
+	return policy, nil
+}
+
+func (p ProxyPolicy) IsTrusted(ip net.IP) bool {
+	if p.TrustPrivate && (ip.IsPrivate() || ip.IsLoopback() || ip.IsLinkLocalUnicast()) {
+		return true
+	}
+
+	for _, network := range p.TrustedProxies {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (r ContextRequest) ClientIP() string {
+	return r.ClientIPWith(DefaultProxyPolicy)
+}
+
+// ClientIPWith walks the forwarding chain from the nearest hop outwards and
+// returns the first address that is not a trusted proxy. When every hop is
+// trusted it returns the nearest one, as entries further out are whatever
+// the client sent.
+func (r ContextRequest) ClientIPWith(policy ProxyPolicy) string {
+	chain := r.forwardedChain(policy.Header)
+
+	for i := len(chain) - 1; i >= 0; i-- {
+		if !policy.IsTrusted(chain[i]) {
+			return chain[i].String()
+		}
+	}
+
+	if len(chain) > 0 {
+		return chain[len(chain)-1].String()
+	}
+
+	return ""
+}
+
+func (r ContextRequest) forwardedChain(name string) []net.IP {
+	chain := []net.IP{}
+
+	if strings.EqualFold(name, "x-real-ip") {
+		if ip := parseForwardedIP(r.Header("x-real-ip")); ip != nil {
+			chain = append(chain, ip)
+		}
+
+		return chain
+	}
+
+	if !strings.EqualFold(name, "forwarded") {
+		for _, header := range r.HeaderValues("x-forwarded-for") {
+			for _, value := range strings.Split(header, ",") {
+				if ip := parseForwardedIP(value); ip != nil {
+					chain = append(chain, ip)
+				}
+			}
+		}
+
+		return chain
+	}
+
+	for _, header := range r.HeaderValues("forwarded") {
+		for _, element := range strings.Split(header, ",") {
+			for _, pair := range strings.Split(element, ";") {
+				key, value, found := strings.Cut(strings.TrimSpace(pair), "=")
+				if !found || !strings.EqualFold(key, "for") {
+					continue
+				}
+
+				if ip := parseForwardedIP(value); ip != nil {
+					chain = append(chain, ip)
+				}
+			}
+		}
+	}
+
+	return chain
+}
+
+func parseForwardedIP(value string) net.IP {
+	value = strings.Trim(strings.TrimSpace(value), "\"")
+
+	if host, _, err := net.SplitHostPort(value); err == nil {
+		value = host
+	}
+
+	value = strings.TrimSuffix(strings.TrimPrefix(value, "["), "]")
+
+	return net.ParseIP(value)
+}
@@ -0,0 +1,103 @@
+package openruntimes
+
+import "testing"
+
+func TestClientIPWith(t *testing.T) {
+	cloudflare, err := NewProxyPolicy("173.245.48.0/20")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	forwarded := DefaultProxyPolicy
+	forwarded.Header = "forwarded"
+
+	realIP := DefaultProxyPolicy
+	realIP.Header = "x-real-ip"
+
+	tests := []struct {
+		name    string
+		headers map[string]string
+		policy  ProxyPolicy
+		want    string
+	}{
+		{
+			name:    "nearest untrusted hop",
+			headers: map[string]string{"x-forwarded-for": "6.6.6.6, 9.9.9.9, 10.0.0.1"},
+			policy:  DefaultProxyPolicy,
+			want:    "9.9.9.9",
+		},
+		{
+			name: "forwarded ignored by default",
+			headers: map[string]string{
+				"forwarded":       "for=6.6.6.6",
+				"x-forwarded-for": "6.6.6.6, 9.9.9.9",
+			},
+			policy: DefaultProxyPolicy,
+			want:   "9.9.9.9",
+		},
+		{
+			name: "x-forwarded-for ignored when forwarded is configured",
+			headers: map[string]string{
+				"forwarded":       "for=9.9.9.9;proto=https",
+				"x-forwarded-for": "6.6.6.6",
+			},
+			policy: forwarded,
+			want:   "9.9.9.9",
+		},
+		{
+			name:    "forwarded with ports and brackets",
+			headers: map[string]string{"forwarded": `for="[2001:db8::1]:4711", for=10.0.0.1`},
+			policy:  forwarded,
+			want:    "2001:db8::1",
+		},
+		{
+			name:    "every hop trusted returns nearest",
+			headers: map[string]string{"x-forwarded-for": "10.9.9.9, 192.168.1.9"},
+			policy:  DefaultProxyPolicy,
+			want:    "192.168.1.9",
+		},
+		{
+			name:    "configured trusted proxies",
+			headers: map[string]string{"x-forwarded-for": "6.6.6.6, 9.9.9.9, 173.245.48.1"},
+			policy:  cloudflare,
+			want:    "9.9.9.9",
+		},
+		{
+			name:    "private addresses untrusted without TrustPrivate",
+			headers: map[string]string{"x-forwarded-for": "9.9.9.9, 10.0.0.1"},
+			policy:  cloudflare,
+			want:    "10.0.0.1",
+		},
+		{
+			name:    "x-real-ip ignored by default",
+			headers: map[string]string{"x-real-ip": "9.9.9.9"},
+			policy:  DefaultProxyPolicy,
+			want:    "",
+		},
+		{
+			name: "x-real-ip when configured",
+			headers: map[string]string{
+				"x-real-ip":       "9.9.9.9",
+				"x-forwarded-for": "6.6.6.6",
+			},
+			policy: realIP,
+			want:   "9.9.9.9",
+		},
+		{
+			name:    "no headers",
+			headers: map[string]string{},
+			policy:  DefaultProxyPolicy,
+			want:    "",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := ContextRequest{Headers: test.headers}
+
+			if got := req.ClientIPWith(test.policy); got != test.want {
+				t.Errorf("ClientIPWith() = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
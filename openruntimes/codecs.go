@@ -0,0 +1,158 @@
+package openruntimes
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/open-runtimes/types-for-go/v4/mimetypes"
+)
+
+type Codec interface {
+	Marshal(v any) ([]byte, error)
+	Unmarshal(data []byte, v any) error
+}
+
+type JsonCodec struct{}
+
+func (JsonCodec) Marshal(v any) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (JsonCodec) Unmarshal(data []byte, v any) error {
+	return json.Unmarshal(data, v)
+}
+
+type XmlCodec struct{}
+
+func (XmlCodec) Marshal(v any) ([]byte, error) {
+	return xml.Marshal(v)
+}
+
+func (XmlCodec) Unmarshal(data []byte, v any) error {
+	return xml.Unmarshal(data, v)
+}
+
+// Codecs that can only encode live in their own registry, so request bodies
+// of those types are never handed to an Unmarshal that always fails.
+var (
+	codecsMutex sync.RWMutex
+	codecs      = map[string]Codec{
+		"application/json": JsonCodec{},
+		"application/xml":  XmlCodec{},
+		"text/xml":         XmlCodec{},
+	}
+	encodeOnlyCodecs = map[string]Codec{
+		"application/yaml":    YamlCodec{},
+		"application/msgpack": MsgpackCodec{},
+	}
+)
+
+func RegisterCodec(mediaType string, codec Codec) {
+	codecsMutex.Lock()
+	defer codecsMutex.Unlock()
+
+	essence := mimetypes.Essence(mediaType)
+	delete(encodeOnlyCodecs, essence)
+	codecs[essence] = codec
+}
+
+// RegisterEncodeOnlyCodec makes a codec available to Encode and Respond
+// without using it to decode request bodies.
+func RegisterEncodeOnlyCodec(mediaType string, codec Codec) {
+	codecsMutex.Lock()
+	defer codecsMutex.Unlock()
+
+	essence := mimetypes.Essence(mediaType)
+	delete(codecs, essence)
+	encodeOnlyCodecs[essence] = codec
+}
+
+// LookupCodec finds the codec that decodes mediaType. It falls back to the
+// structured syntax suffix, so "application/vnd.api+json" resolves to the
+// JSON codec.
+func LookupCodec(mediaType string) (Codec, bool) {
+	codecsMutex.RLock()
+	defer codecsMutex.RUnlock()
+
+	return lookupIn(codecs, mediaType)
+}
+
+// lookupEncodingCodec is LookupCodec including encode-only codecs.
+func lookupEncodingCodec(mediaType string) (Codec, bool) {
+	codecsMutex.RLock()
+	defer codecsMutex.RUnlock()
+
+	if codec, ok := lookupIn(codecs, mediaType); ok {
+		return codec, true
+	}
+	return lookupIn(encodeOnlyCodecs, mediaType)
+}
+
+func lookupIn(registry map[string]Codec, mediaType string) (Codec, bool) {
+	essence := mimetypes.Essence(mediaType)
+
+	if codec, ok := registry[essence]; ok {
+		return codec, true
+	}
+
+	if index := strings.LastIndex(essence, "+"); index != -1 {
+		if codec, ok := registry["application/"+essence[index+1:]]; ok {
+			return codec, true
+		}
+	}
+
+	return nil, false
+}
+
+// RegisteredMediaTypes lists every type Encode can produce, encode-only
+// codecs included.
+func RegisteredMediaTypes() []string {
+	codecsMutex.RLock()
+	defer codecsMutex.RUnlock()
+
+	mediaTypes := []string{}
+	for _, registry := range []map[string]Codec{codecs, encodeOnlyCodecs} {
+		for mediaType := range registry {
+			mediaTypes = append(mediaTypes, mediaType)
+		}
+	}
+	sort.Strings(mediaTypes)
+
+	return mediaTypes
+}
+
+func (r ContextRequest) Decode(v any) error {
+	contentType, _ := r.ContentType()
+
+	codec, ok := LookupCodec(contentType)
+	if !ok {
+		return errors.New("no codec registered for content type " + contentType)
+	}
+
+	if err := codec.Unmarshal(r.BodyBinary(), v); err != nil {
+		return errors.New("could not decode body as " + contentType)
+	}
+
+	return nil
+}
+
+func (r ContextResponse) Encode(contentType string, v any, optionalSetters ...ResponseOption) Response {
+	codec, ok := lookupEncodingCodec(contentType)
+	if !ok {
+		optionalSetters = append(optionalSetters, r.WithStatusCode(500))
+		return r.Text("No codec registered for "+contentType+".", optionalSetters...)
+	}
+
+	data, err := codec.Marshal(v)
+	if err != nil {
+		optionalSetters = append(optionalSetters, r.WithStatusCode(500))
+		return r.Text("Error encoding "+contentType+".", optionalSetters...)
+	}
+
+	optionalSetters = append(optionalSetters, r.WithHeaders(map[string]string{"content-type": contentType}))
+	return r.Binary(data, optionalSetters...)
+}
@@ -0,0 +1,50 @@
+package openruntimes
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDecodeSkipsEncodeOnlyCodecs(t *testing.T) {
+	for _, contentType := range []string{"application/yaml", "application/msgpack"} {
+		t.Run(contentType, func(t *testing.T) {
+			if _, ok := LookupCodec(contentType); ok {
+				t.Errorf("LookupCodec(%q) found an encode-only codec", contentType)
+			}
+
+			req := ContextRequest{Headers: map[string]string{"content-type": contentType}}
+			req.SetBodyBinary([]byte("name: a"))
+
+			var v map[string]any
+			if err := req.Decode(&v); err == nil || !strings.HasPrefix(err.Error(), "no codec registered") {
+				t.Errorf("Decode() error = %v, want no codec registered", err)
+			}
+		})
+	}
+}
+
+func TestDecodeByContentType(t *testing.T) {
+	tests := []struct {
+		contentType string
+		body        string
+	}{
+		{"application/json", `{"name":"a"}`},
+		{"application/vnd.api+json", `{"name":"a"}`},
+		{"application/xml", `<item><name>a</name></item>`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.contentType, func(t *testing.T) {
+			req := ContextRequest{Headers: map[string]string{"content-type": test.contentType}}
+			req.SetBodyBinary([]byte(test.body))
+
+			var item negotiationItem
+			if err := req.Decode(&item); err != nil {
+				t.Fatalf("Decode() error = %v", err)
+			}
+			if item.Name != "a" {
+				t.Errorf("Name = %q, want %q", item.Name, "a")
+			}
+		})
+	}
+}
@@ -0,0 +1,29 @@
+package openruntimes
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+const METRIC_COLD_START = "cold_start"
+const METRIC_INIT_DURATION = "init_duration"
+
+var processStartedAt = time.Now()
+var processInvoked atomic.Bool
+
+// markInvocation reports whether this is the first invocation in the process,
+// along with the time spent between process start and that invocation.
+func markInvocation() (bool, time.Duration) {
+	if processInvoked.CompareAndSwap(false, true) {
+		return true, time.Since(processStartedAt)
+	}
+	return false, 0
+}
+
+func (c *Context) IsColdStart() bool {
+	return c.coldStart
+}
+
+func (c *Context) InitDuration() time.Duration {
+	return c.initDuration
+}
@@ -0,0 +1,301 @@
+package openruntimes
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/open-runtimes/types-for-go/v4/mimetypes"
+)
+
+type EncoderFunc func(io.Writer) (io.WriteCloser, error)
+
+var (
+	encodersMutex sync.RWMutex
+	encoders      = map[string]EncoderFunc{
+		"gzip": func(writer io.Writer) (io.WriteCloser, error) {
+			return gzip.NewWriter(writer), nil
+		},
+	}
+)
+
+// EncoderPreference breaks ties between encodings the client accepts with equal quality.
+var EncoderPreference = []string{"br", "zstd", "gzip", "deflate"}
+
+// RegisterEncoder makes an encoding negotiable, for example "br" backed by a
+// brotli package, without this module depending on it.
+func RegisterEncoder(name string, fn EncoderFunc) {
+	encodersMutex.Lock()
+	defer encodersMutex.Unlock()
+
+	encoders[strings.ToLower(name)] = fn
+}
+
+func lookupEncoder(name string) (EncoderFunc, bool) {
+	encodersMutex.RLock()
+	defer encodersMutex.RUnlock()
+
+	fn, ok := encoders[name]
+	return fn, ok
+}
+
+func encoderRank(name string) int {
+	for index, preferred := range EncoderPreference {
+		if preferred == name {
+			return index
+		}
+	}
+	return len(EncoderPreference)
+}
+
+func (r ContextResponse) WithCompression() ResponseOption {
+	return func(o *Response) {
+		o.compression = "gzip"
+	}
+}
+
+// WithCompressionFor compresses only when the request's Accept-Encoding allows it.
+func (r ContextResponse) WithCompressionFor(req ContextRequest) ResponseOption {
+	return func(o *Response) {
+		o.compression = NegotiateEncoding(req.Header("accept-encoding"))
+	}
+}
+
+func (r ContextResponse) WithEncoding(encoding string) ResponseOption {
+	return func(o *Response) {
+		o.compression = strings.ToLower(encoding)
+	}
+}
+
+func NegotiateEncoding(acceptEncoding string) string {
+	best := ""
+	bestQuality := 0.0
+
+	qualities := map[string]float64{}
+	wildcard := -1.0
+
+	for _, entry := range strings.Split(acceptEncoding, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(entry), ";")
+		name = strings.ToLower(strings.TrimSpace(name))
+
+		quality := 1.0
+		if key, value, found := strings.Cut(strings.TrimSpace(params), "="); found && strings.TrimSpace(key) == "q" {
+			if parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil {
+				quality = parsed
+			}
+		}
+
+		if name == "*" {
+			wildcard = quality
+			continue
+		}
+
+		qualities[name] = quality
+	}
+
+	encodersMutex.RLock()
+	names := []string{}
+	for name := range encoders {
+		names = append(names, name)
+	}
+	encodersMutex.RUnlock()
+
+	sort.Slice(names, func(i, j int) bool {
+		if encoderRank(names[i]) != encoderRank(names[j]) {
+			return encoderRank(names[i]) < encoderRank(names[j])
+		}
+		return names[i] < names[j]
+	})
+
+	for _, name := range names {
+		quality, ok := qualities[name]
+		if !ok {
+			quality = wildcard
+		}
+
+		if quality > bestQuality {
+			best = name
+			bestQuality = quality
+		}
+	}
+
+	return best
+}
+
+func compressBody(encoding string, body []byte) ([]byte, error) {
+	var buffer bytes.Buffer
+
+	writer, err := newEncodingWriter(encoding, &buffer)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := writer.Write(body); err != nil {
+		return nil, err
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
+
+func compressReader(encoding string, reader io.Reader) io.Reader {
+	pipeReader, pipeWriter := io.Pipe()
+
+	go func() {
+		if closer, ok := reader.(io.Closer); ok {
+			defer closer.Close()
+		}
+
+		writer, err := newEncodingWriter(encoding, pipeWriter)
+		if err != nil {
+			pipeWriter.CloseWithError(err)
+			return
+		}
+
+		if _, err := io.Copy(writer, reader); err != nil {
+			pipeWriter.CloseWithError(err)
+			return
+		}
+
+		pipeWriter.CloseWithError(writer.Close())
+	}()
+
+	return pipeReader
+}
+
+func newEncodingWriter(encoding string, writer io.Writer) (io.WriteCloser, error) {
+	fn, ok := lookupEncoder(encoding)
+	if !ok {
+		return nil, errors.New("encoder " + encoding + " is not registered")
+	}
+
+	return fn(writer)
+}
+
+func compressedHeaders(headers map[string]string, encoding string, length int) map[string]string {
+	compressed := map[string]string{}
+	for key, value := range headers {
+		compressed[key] = value
+	}
+
+	headerSet(compressed, "content-encoding", encoding)
+
+	addVary(compressed, "Accept-Encoding")
+
+	if _, ok := headerGet(compressed, "content-length"); ok {
+		if length >= 0 {
+			headerSet(compressed, "content-length", strconv.Itoa(length))
+		} else {
+			headerDelete(compressed, "content-length")
+		}
+	}
+
+	return compressed
+}
+
+const COMPRESSION_MIN_SIZE = 1024
+
+// CompressOptions configures Compress. Bodies smaller than MinSize are sent
+// as-is; SkipTypes adds media types, or "type/*" ranges, to the ones that are
+// already compressed and never re-encoded.
+type CompressOptions struct {
+	MinSize   int
+	SkipTypes []string
+}
+
+var compressedMediaTypes = []string{
+	"image/*", "video/*", "audio/*", "font/woff", "font/woff2",
+	"application/zip", "application/gzip", "application/x-gzip", "application/zstd",
+	"application/x-7z-compressed", "application/x-rar-compressed", "application/pdf",
+}
+
+func (o CompressOptions) skips(contentType string) bool {
+	essence := mimetypes.Essence(contentType)
+	if essence == "image/svg+xml" {
+		return false
+	}
+
+	for _, skipped := range append(append([]string{}, compressedMediaTypes...), o.SkipTypes...) {
+		skipped = strings.ToLower(skipped)
+		if skipped == essence || (strings.HasSuffix(skipped, "/*") && strings.HasPrefix(essence, strings.TrimSuffix(skipped, "*"))) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Compress encodes any handler's response with the best encoding the
+// request's Accept-Encoding allows, unless it is already encoded, too small,
+// of an already compressed type, a partial response whose byte offsets the
+// client relies on, or has no body. Strong ETags are weakened, since the
+// bytes on the wire change.
+func Compress(options CompressOptions) Middleware {
+	if options.MinSize == 0 {
+		options.MinSize = COMPRESSION_MIN_SIZE
+	}
+
+	return func(next Handler) Handler {
+		return func(c *Context) Response {
+			response := next(c)
+
+			if response.StatusCode == 204 || response.StatusCode == 206 || response.StatusCode == 304 || strings.EqualFold(c.Req.Method, "HEAD") {
+				return response
+			}
+
+			if contentRange, _ := headerGet(response.Headers, "content-range"); contentRange != "" {
+				return response
+			}
+
+			if encoding, _ := headerGet(response.Headers, "content-encoding"); encoding != "" && !strings.EqualFold(encoding, "identity") {
+				return response
+			}
+
+			contentType, _ := headerGet(response.Headers, "content-type")
+			if options.skips(contentType) {
+				return response
+			}
+
+			if !response.IsStream() && len(response.Body) < options.MinSize {
+				return response
+			}
+
+			encoding := NegotiateEncoding(c.Req.Header("accept-encoding"))
+			if encoding == "" {
+				headers := cloneStringMap(response.Headers)
+				if headers == nil {
+					headers = map[string]string{}
+				}
+				addVary(headers, "Accept-Encoding")
+				response.Headers = headers
+				return response
+			}
+
+			if response.IsStream() {
+				response.BodyReader = compressReader(encoding, response.BodyReader)
+				response.Headers = compressedHeaders(response.Headers, encoding, -1)
+			} else {
+				compressed, err := compressBody(encoding, response.Body)
+				if err != nil {
+					return response
+				}
+				response.Body = compressed
+				response.Headers = compressedHeaders(response.Headers, encoding, len(compressed))
+			}
+
+			if etag, _ := headerGet(response.Headers, "etag"); etag != "" && !strings.HasPrefix(etag, "W/") {
+				headerSet(response.Headers, "etag", "W/"+etag)
+			}
+
+			return response
+		}
+	}
+}
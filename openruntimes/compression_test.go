@@ -0,0 +1,50 @@
+package openruntimes
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestCompress(t *testing.T) {
+	large := strings.Repeat("compressible text ", 200)
+
+	tests := []struct {
+		name       string
+		method     string
+		response   func(c *Context) Response
+		compressed bool
+	}{
+		{"large text", "GET", func(c *Context) Response { return c.Res.Text(large) }, true},
+		{"small text", "GET", func(c *Context) Response { return c.Res.Text("short") }, false},
+		{"head request", "HEAD", func(c *Context) Response { return c.Res.Text(large) }, false},
+		{"already compressed type", "GET", func(c *Context) Response {
+			return c.Res.Binary([]byte(large), c.Res.WithHeaders(map[string]string{"content-type": "image/png"}))
+		}, false},
+		{"partial content", "GET", func(c *Context) Response {
+			return c.Res.Text(large, c.Res.WithStatusCode(206))
+		}, false},
+		{"content-range", "GET", func(c *Context) Response {
+			return c.Res.Text(large, c.Res.WithHeaders(map[string]string{"content-range": "bytes 0-3599/9000"}))
+		}, false},
+		{"byte range", "GET", func(c *Context) Response {
+			c.Req.SetHeader("range", "bytes=0-2999")
+			return c.Res.BinaryRange(bytes.NewReader([]byte(large)), int64(len(large)), c.Req)
+		}, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := NewContext(Logger{})
+			c.Req.Method = test.method
+			c.Req.Headers = map[string]string{"accept-encoding": "gzip"}
+
+			response := Compress(CompressOptions{})(test.response)(&c)
+
+			encoding, _ := headerGet(response.Headers, "content-encoding")
+			if compressed := encoding == "gzip"; compressed != test.compressed {
+				t.Errorf("compressed = %v, want %v (status %d)", compressed, test.compressed, response.StatusCode)
+			}
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package openruntimes
+
+import (
+	"net/http"
+	"strings"
+	"time"
+)
+
+func (r ContextRequest) IfNoneMatch() []string {
+	return parseETagList(strings.Join(r.HeaderValues("if-none-match"), ","))
+}
+
+func (r ContextRequest) IfMatch() []string {
+	return parseETagList(strings.Join(r.HeaderValues("if-match"), ","))
+}
+
+func (r ContextRequest) IfModifiedSince() (time.Time, bool) {
+	header := r.Header("if-modified-since")
+	if header == "" {
+		return time.Time{}, false
+	}
+
+	modifiedSince, err := http.ParseTime(header)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	return modifiedSince, true
+}
+
+// IsNotModified applies RFC 9110 precedence: If-None-Match wins over If-Modified-Since.
+func (r ContextRequest) IsNotModified(etag string, lastModified time.Time) bool {
+	if etags := r.IfNoneMatch(); len(etags) > 0 {
+		return MatchETag(etags, etag, true)
+	}
+
+	if modifiedSince, ok := r.IfModifiedSince(); ok && !lastModified.IsZero() {
+		return !lastModified.Truncate(time.Second).After(modifiedSince)
+	}
+
+	return false
+}
+
+// IfRangeMatches reports whether a Range request may be honoured: true
+// without an If-Range header, otherwise only when it names etag by strong
+// comparison or equals lastModified exactly.
+func (r ContextRequest) IfRangeMatches(etag string, lastModified time.Time) bool {
+	header := strings.TrimSpace(r.Header("if-range"))
+	if header == "" {
+		return true
+	}
+
+	if strings.HasPrefix(header, "\"") || strings.HasPrefix(header, "W/") {
+		return MatchETag(parseETagList(header), etag, false)
+	}
+
+	date, err := http.ParseTime(header)
+	if err != nil || lastModified.IsZero() {
+		return false
+	}
+	return lastModified.Truncate(time.Second).Equal(date)
+}
+
+func MatchETag(etags []string, etag string, weak bool) bool {
+	if etag == "" {
+		return false
+	}
+
+	for _, candidate := range etags {
+		if candidate == "*" {
+			return true
+		}
+
+		if weak {
+			if strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+				return true
+			}
+		} else if candidate == etag && !strings.HasPrefix(etag, "W/") {
+			return true
+		}
+	}
+
+	return false
+}
+
+func parseETagList(header string) []string {
+	etags := []string{}
+	header = strings.TrimSpace(header)
+
+	for header != "" {
+		header = strings.TrimLeft(header, " \t,")
+		if header == "" {
+			break
+		}
+
+		if header[0] == '*' {
+			etags = append(etags, "*")
+			header = header[1:]
+			continue
+		}
+
+		prefix := ""
+		if strings.HasPrefix(header, "W/") {
+			prefix = "W/"
+			header = header[2:]
+		}
+
+		if header == "" || header[0] != '"' {
+			break
+		}
+
+		end := strings.Index(header[1:], "\"")
+		if end == -1 {
+			break
+		}
+
+		etags = append(etags, prefix+header[:end+2])
+		header = header[end+2:]
+	}
+
+	return etags
+}
+
+func (r ContextResponse) NotModified(optionalSetters ...ResponseOption) Response {
+	optionalSetters = append(optionalSetters, r.WithStatusCode(304))
+	return r.Binary([]byte{}, optionalSetters...)
+}
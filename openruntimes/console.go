@@ -0,0 +1,61 @@
+package openruntimes
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// The terminal as it was before native log capture swapped os.Stdout and
+// os.Stderr for pipes; mirroring into the pipes would log every line twice.
+var (
+	consoleLogs   io.Writer = os.Stdout
+	consoleErrors io.Writer = os.Stderr
+)
+
+var consoleColors = map[string]string{
+	LOGGER_TYPE_DEBUG: "\033[90m",
+	LOGGER_TYPE_INFO:  "\033[36m",
+	LOGGER_TYPE_LOG:   "\033[32m",
+	LOGGER_TYPE_WARN:  "\033[33m",
+	LOGGER_TYPE_ERROR: "\033[31m",
+}
+
+const consoleReset = "\033[0m"
+const consoleDim = "\033[2m"
+
+// mirrorToConsole echoes a record to the terminal with a time and a colored
+// level at the start of each line. Colors are left out when NO_COLOR is set.
+func (l *Logger) mirrorToConsole(xtype string, text string) {
+	if !l.Console || text == "" {
+		return
+	}
+
+	console := consoleLogs
+	if xtype == LOGGER_TYPE_ERROR || xtype == LOGGER_TYPE_WARN {
+		console = consoleErrors
+	}
+
+	color, reset, dim := consoleColors[xtype], consoleReset, consoleDim
+	if _, ok := os.LookupEnv("NO_COLOR"); ok {
+		color, reset, dim = "", "", ""
+	}
+
+	prefix := dim + time.Now().Format("15:04:05") + reset + " " + color + fmt.Sprintf("%-5s ", strings.ToUpper(xtype)) + reset
+
+	var builder strings.Builder
+	for _, line := range strings.SplitAfter(text, "\n") {
+		if line == "" {
+			continue
+		}
+		if !l.midLineConsole && line != "\n" {
+			builder.WriteString(prefix)
+		}
+		builder.WriteString(line)
+		l.midLineConsole = !strings.HasSuffix(line, "\n")
+	}
+
+	console.Write([]byte(builder.String()))
+}
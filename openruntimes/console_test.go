@@ -0,0 +1,28 @@
+package openruntimes
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestMirrorToConsolePadsLevels(t *testing.T) {
+	t.Setenv("NO_COLOR", "1")
+
+	var console bytes.Buffer
+	previous := consoleLogs
+	consoleLogs = &console
+	t.Cleanup(func() { consoleLogs = previous })
+
+	logger, _ := newBufferLogger(t, LOGGER_FORMAT_TEXT, false)
+	logger.Console = true
+
+	logger.Write([]interface{}{"short\n"}, LOGGER_TYPE_LOG, false)
+	logger.Write([]interface{}{"long\n"}, "critical", false)
+
+	for _, want := range []string{" LOG   short", " CRITICAL long"} {
+		if !strings.Contains(console.String(), want) {
+			t.Errorf("console = %q, want %q", console.String(), want)
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package openruntimes
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+const (
+	SCOPE_EXECUTION = "execution"
+	SCOPE_PROCESS   = "process"
+)
+
+var ErrNotProvided = errors.New("no provider registered")
+
+type Constructor func(*Context) (any, error)
+
+// processEntry holds one process-scoped dependency. It is built at most once
+// per warm container; a failed build is retried on the next Resolve.
+type processEntry struct {
+	mutex       sync.Mutex
+	constructor Constructor
+	value       any
+	built       bool
+}
+
+var (
+	processMutex   sync.Mutex
+	processEntries = map[string]*processEntry{}
+)
+
+// executionContainer is shared by copies of a Context.
+type executionContainer struct {
+	mutex        sync.Mutex
+	constructors map[string]Constructor
+	values       map[string]any
+}
+
+// Provide registers how to build the dependency named key. SCOPE_EXECUTION
+// builds it once per execution; SCOPE_PROCESS builds it once per warm
+// container and shares it across executions, so its constructor must not
+// keep the Context it is given. Registering a process-scoped key again, as
+// every execution does, keeps the first registration.
+func (c *Context) Provide(key string, scope string, constructor Constructor) {
+	if scope == SCOPE_PROCESS {
+		processMutex.Lock()
+		defer processMutex.Unlock()
+
+		if _, ok := processEntries[key]; !ok {
+			processEntries[key] = &processEntry{constructor: constructor}
+		}
+		return
+	}
+
+	if c.container == nil {
+		c.container = &executionContainer{}
+	}
+
+	c.container.mutex.Lock()
+	defer c.container.mutex.Unlock()
+
+	if c.container.constructors == nil {
+		c.container.constructors = map[string]Constructor{}
+		c.container.values = map[string]any{}
+	}
+	c.container.constructors[key] = constructor
+	delete(c.container.values, key)
+}
+
+func (c *Context) resolve(key string) (any, error) {
+	if c.container != nil {
+		c.container.mutex.Lock()
+		constructor, ok := c.container.constructors[key]
+		value, built := c.container.values[key]
+		c.container.mutex.Unlock()
+
+		if built {
+			return value, nil
+		}
+
+		if ok {
+			// Built without the lock held, so constructors can resolve their
+			// own dependencies; if two goroutines race, the first value wins.
+			value, err := constructor(c)
+			if err != nil {
+				return nil, err
+			}
+
+			c.container.mutex.Lock()
+			defer c.container.mutex.Unlock()
+
+			if existing, built := c.container.values[key]; built {
+				return existing, nil
+			}
+			c.container.values[key] = value
+			return value, nil
+		}
+	}
+
+	processMutex.Lock()
+	entry, ok := processEntries[key]
+	processMutex.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrNotProvided, key)
+	}
+
+	entry.mutex.Lock()
+	defer entry.mutex.Unlock()
+
+	if !entry.built {
+		value, err := entry.constructor(c)
+		if err != nil {
+			return nil, err
+		}
+		entry.value, entry.built = value, true
+	}
+
+	return entry.value, nil
+}
+
+// Resolve builds or reuses the dependency named key, preferring an
+// execution-scoped provider over a process-scoped one.
+func Resolve[T any](c *Context, key string) (T, error) {
+	var zero T
+
+	value, err := c.resolve(key)
+	if err != nil {
+		return zero, err
+	}
+
+	typed, ok := value.(T)
+	if !ok {
+		return zero, fmt.Errorf("dependency %s is %T, not %T", key, value, zero)
+	}
+	return typed, nil
+}
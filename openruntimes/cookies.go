@@ -0,0 +1,81 @@
+package openruntimes
+
+import (
+	"net/http"
+	"strings"
+	"time"
+)
+
+func (r ContextRequest) Cookies() map[string]string {
+	request := http.Request{
+		Header: http.Header{"Cookie": r.HeaderValues("cookie")},
+	}
+
+	cookies := map[string]string{}
+	for _, cookie := range request.Cookies() {
+		if _, ok := cookies[cookie.Name]; !ok {
+			cookies[cookie.Name] = cookie.Value
+		}
+	}
+
+	return cookies
+}
+
+func (r ContextRequest) Cookie(name string) (string, bool) {
+	value, ok := r.Cookies()[name]
+	return value, ok
+}
+
+type Cookie struct {
+	Name     string
+	Value    string
+	Path     string
+	Domain   string
+	Expires  time.Time
+	MaxAge   int
+	Secure   bool
+	HttpOnly bool
+	SameSite string
+}
+
+// String serializes the cookie as a Set-Cookie value. MaxAge follows
+// net/http: 0 omits the attribute and a negative value expires the cookie.
+func (c Cookie) String() string {
+	cookie := http.Cookie{
+		Name:     c.Name,
+		Value:    c.Value,
+		Path:     c.Path,
+		Domain:   c.Domain,
+		Expires:  c.Expires,
+		MaxAge:   c.MaxAge,
+		Secure:   c.Secure,
+		HttpOnly: c.HttpOnly,
+	}
+
+	switch strings.ToLower(c.SameSite) {
+	case "lax":
+		cookie.SameSite = http.SameSiteLaxMode
+	case "strict":
+		cookie.SameSite = http.SameSiteStrictMode
+	case "none":
+		cookie.SameSite = http.SameSiteNoneMode
+	}
+
+	return cookie.String()
+}
+
+func (r ContextResponse) WithCookie(cookie Cookie) ResponseOption {
+	return func(o *Response) {
+		o.Cookies = append(o.Cookies, cookie)
+	}
+}
+
+func (r Response) SetCookieHeaders() []string {
+	headers := []string{}
+	for _, cookie := range r.Cookies {
+		if header := cookie.String(); header != "" {
+			headers = append(headers, header)
+		}
+	}
+	return headers
+}
@@ -0,0 +1,123 @@
+package openruntimes
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CorsOptions configures Cors. AllowOrigins entries are "*", an exact origin
+// or a pattern with one wildcard such as "https://*.example.com"; it defaults
+// to "*". With AllowCredentials the matching origin is echoed instead of "*",
+// as browsers require. An empty AllowHeaders allows whatever headers a
+// preflight asks for.
+type CorsOptions struct {
+	AllowOrigins     []string
+	AllowMethods     []string
+	AllowHeaders     []string
+	ExposeHeaders    []string
+	MaxAge           time.Duration
+	AllowCredentials bool
+}
+
+func (o CorsOptions) allowsOrigin(origin string) bool {
+	for _, allowed := range o.AllowOrigins {
+		if allowed == "*" || strings.EqualFold(allowed, origin) {
+			return true
+		}
+
+		prefix, suffix, found := strings.Cut(strings.ToLower(allowed), "*")
+		lowered := strings.ToLower(origin)
+		if found && len(lowered) > len(prefix)+len(suffix) && strings.HasPrefix(lowered, prefix) && strings.HasSuffix(lowered, suffix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (o CorsOptions) allowOriginValue(origin string) string {
+	if !o.AllowCredentials && len(o.AllowOrigins) == 1 && o.AllowOrigins[0] == "*" {
+		return "*"
+	}
+	return origin
+}
+
+// Cors answers preflight requests itself and adds the CORS headers to every
+// other response for an allowed origin. Requests without an Origin header
+// pass through untouched.
+func Cors(options CorsOptions) Middleware {
+	if len(options.AllowOrigins) == 0 {
+		options.AllowOrigins = []string{"*"}
+	}
+	if len(options.AllowMethods) == 0 {
+		options.AllowMethods = []string{"GET", "HEAD", "PUT", "PATCH", "POST", "DELETE"}
+	}
+
+	return func(next Handler) Handler {
+		return func(c *Context) Response {
+			origin := c.Req.Header("origin")
+			if origin == "" {
+				return next(c)
+			}
+
+			requestedMethod := c.Req.Header("access-control-request-method")
+			if strings.EqualFold(c.Req.Method, "OPTIONS") && requestedMethod != "" {
+				return preflight(c, options, origin)
+			}
+
+			response := next(c)
+
+			headers := cloneStringMap(response.Headers)
+			if headers == nil {
+				headers = map[string]string{}
+			}
+			// Even refusals vary by origin, or a cache could replay them to allowed ones.
+			addVary(headers, "Origin")
+			response.Headers = headers
+
+			if !options.allowsOrigin(origin) {
+				return response
+			}
+
+			headerSet(headers, "access-control-allow-origin", options.allowOriginValue(origin))
+			if options.AllowCredentials {
+				headerSet(headers, "access-control-allow-credentials", "true")
+			}
+			if len(options.ExposeHeaders) > 0 {
+				headerSet(headers, "access-control-expose-headers", strings.Join(options.ExposeHeaders, ", "))
+			}
+
+			return response
+		}
+	}
+}
+
+func preflight(c *Context, options CorsOptions, origin string) Response {
+	headers := map[string]string{}
+	addVary(headers, "Origin")
+	addVary(headers, "Access-Control-Request-Method")
+	addVary(headers, "Access-Control-Request-Headers")
+
+	if !options.allowsOrigin(origin) {
+		return c.Res.Binary([]byte{}, c.Res.WithStatusCode(204), c.Res.WithHeaders(headers))
+	}
+
+	headers["access-control-allow-origin"] = options.allowOriginValue(origin)
+	headers["access-control-allow-methods"] = strings.Join(options.AllowMethods, ", ")
+
+	if len(options.AllowHeaders) > 0 {
+		headers["access-control-allow-headers"] = strings.Join(options.AllowHeaders, ", ")
+	} else if requested := c.Req.Header("access-control-request-headers"); requested != "" {
+		headers["access-control-allow-headers"] = requested
+	}
+
+	if options.AllowCredentials {
+		headers["access-control-allow-credentials"] = "true"
+	}
+	if options.MaxAge > 0 {
+		headers["access-control-max-age"] = strconv.Itoa(int(options.MaxAge.Seconds()))
+	}
+
+	return c.Res.Binary([]byte{}, c.Res.WithStatusCode(204), c.Res.WithHeaders(headers))
+}
@@ -0,0 +1,75 @@
+package openruntimes
+
+import (
+	"bytes"
+	"encoding/csv"
+	"io"
+)
+
+const CSV_BOM = "\xef\xbb\xbf"
+
+// WithBOM prefixes CSV output with a UTF-8 byte order mark so Excel detects the encoding.
+func (r ContextResponse) WithBOM() ResponseOption {
+	return func(o *Response) {
+		o.enabledSetters["CsvBOM"] = true
+	}
+}
+
+func (r ContextResponse) Csv(rows [][]string, optionalSetters ...ResponseOption) Response {
+	options := Response{}.New()
+	for _, opt := range optionalSetters {
+		opt(options)
+	}
+
+	var body bytes.Buffer
+	if options.enabledSetters["CsvBOM"] {
+		body.WriteString(CSV_BOM)
+	}
+
+	writer := csv.NewWriter(&body)
+	if err := writer.WriteAll(rows); err != nil {
+		optionalSetters = append(optionalSetters, r.WithStatusCode(500))
+		return r.Text("Error encoding CSV.", optionalSetters...)
+	}
+
+	optionalSetters = append(optionalSetters, r.WithHeaders(map[string]string{
+		"content-type": "text/csv; charset=utf-8",
+	}))
+
+	return r.Binary(body.Bytes(), optionalSetters...)
+}
+
+// CsvStream writes rows produced by write straight to the client, so large
+// exports are never fully buffered. An error from write aborts the stream.
+func (r ContextResponse) CsvStream(write func(writer *csv.Writer) error, optionalSetters ...ResponseOption) Response {
+	options := Response{}.New()
+	for _, opt := range optionalSetters {
+		opt(options)
+	}
+
+	pipeReader, pipeWriter := io.Pipe()
+
+	go func() {
+		if options.enabledSetters["CsvBOM"] {
+			if _, err := pipeWriter.Write([]byte(CSV_BOM)); err != nil {
+				pipeWriter.CloseWithError(err)
+				return
+			}
+		}
+
+		writer := csv.NewWriter(pipeWriter)
+		if err := write(writer); err != nil {
+			pipeWriter.CloseWithError(err)
+			return
+		}
+
+		writer.Flush()
+		pipeWriter.CloseWithError(writer.Error())
+	}()
+
+	optionalSetters = append(optionalSetters, r.WithHeaders(map[string]string{
+		"content-type": "text/csv; charset=utf-8",
+	}))
+
+	return r.Stream(pipeReader, optionalSetters...)
+}
@@ -0,0 +1,52 @@
+package openruntimes
+
+import (
+	"context"
+	"math"
+	"strconv"
+	"time"
+)
+
+// HEADER_TIMEOUT carries the function's configured timeout in seconds.
+const HEADER_TIMEOUT = "x-open-runtimes-timeout"
+
+// EXECUTION_TIMEOUT_MAX caps the announced timeout, which is converted to a
+// Duration that cannot hold much more than 290 years.
+const EXECUTION_TIMEOUT_MAX = 24 * time.Hour
+
+// withExecutionTimeout bounds ctx by the timeout the executor announced, if any.
+func withExecutionTimeout(parent context.Context, req ContextRequest) (context.Context, context.CancelFunc) {
+	ctx, cancel := withDisconnect(parent)
+
+	seconds, err := strconv.ParseFloat(req.Header(HEADER_TIMEOUT), 64)
+	if err != nil || math.IsNaN(seconds) || math.IsInf(seconds, 0) || seconds <= 0 {
+		return ctx, cancel
+	}
+	seconds = math.Min(seconds, EXECUTION_TIMEOUT_MAX.Seconds())
+
+	ctx, cancelTimeout := context.WithTimeout(ctx, time.Duration(seconds*float64(time.Second)))
+	return ctx, func() {
+		cancelTimeout()
+		cancel()
+	}
+}
+
+// Deadline reports when the execution will be stopped, as Context().Deadline does.
+func (c *Context) Deadline() (time.Time, bool) {
+	return c.Context().Deadline()
+}
+
+// RemainingTime is how long the execution has left, never negative. Without
+// a deadline it is the largest possible Duration.
+func (c *Context) RemainingTime() time.Duration {
+	deadline, ok := c.Deadline()
+	if !ok {
+		return time.Duration(math.MaxInt64)
+	}
+
+	remaining := time.Until(deadline)
+	if remaining < 0 {
+		return 0
+	}
+	return remaining
+}
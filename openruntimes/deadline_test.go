@@ -0,0 +1,45 @@
+package openruntimes
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestWithExecutionTimeout(t *testing.T) {
+	tests := []struct {
+		header   string
+		deadline bool
+		max      time.Duration
+	}{
+		{header: "", deadline: false},
+		{header: "1.5", deadline: true, max: 1500 * time.Millisecond},
+		{header: "0", deadline: false},
+		{header: "-5", deadline: false},
+		{header: "NaN", deadline: false},
+		{header: "Inf", deadline: false},
+		{header: "-Inf", deadline: false},
+		{header: "1e300", deadline: true, max: EXECUTION_TIMEOUT_MAX},
+	}
+
+	for _, test := range tests {
+		t.Run(test.header, func(t *testing.T) {
+			req := ContextRequest{Headers: map[string]string{HEADER_TIMEOUT: test.header}}
+
+			ctx, cancel := withExecutionTimeout(context.Background(), req)
+			defer cancel()
+
+			deadline, ok := ctx.Deadline()
+			if ok != test.deadline {
+				t.Fatalf("deadline set = %v, want %v", ok, test.deadline)
+			}
+			if !ok {
+				return
+			}
+
+			if remaining := time.Until(deadline); remaining <= 0 || remaining > test.max {
+				t.Errorf("remaining = %v, want within (0, %v]", remaining, test.max)
+			}
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package openruntimes
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// WithDeprecation emits the RFC 9745 Deprecation header and, when link is
+// set, a Link with rel="deprecation" pointing at migration docs.
+func (r ContextResponse) WithDeprecation(date time.Time, link string) ResponseOption {
+	return func(o *Response) {
+		headers := map[string]string{
+			"deprecation": "@" + strconv.FormatInt(date.Unix(), 10),
+		}
+
+		if link != "" {
+			headers["link"] = appendLink(o, "<"+link+">; rel=\"deprecation\"")
+		}
+
+		o.mergeHeaders(headers)
+	}
+}
+
+// WithSunset emits the RFC 8594 Sunset header announcing when the endpoint stops responding.
+func (r ContextResponse) WithSunset(t time.Time) ResponseOption {
+	return func(o *Response) {
+		o.mergeHeaders(map[string]string{
+			"sunset": t.UTC().Format(http.TimeFormat),
+		})
+	}
+}
+
+func appendLink(o *Response, link string) string {
+	if existing, _ := headerGet(o.Headers, "link"); o.enabledSetters["Headers"] && existing != "" {
+		return existing + ", " + link
+	}
+	return link
+}
@@ -0,0 +1,133 @@
+package openruntimes
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+const LOGS_DIR_DEFAULT = "/mnt/logs"
+
+// LoggerOptions overrides where logs go and how much of them is kept. A
+// writer takes precedence over a file in Dir; Dir falls back to
+// OPEN_RUNTIMES_LOGS_DIR and then /mnt/logs.
+type LoggerOptions struct {
+	Dir          string
+	LogsWriter   io.Writer
+	ErrorsWriter io.Writer
+	AuditWriter  io.Writer
+
+	// MaxBytes caps each stream per execution; MaxFiles allows rotating files.
+	MaxBytes int64
+	MaxFiles int
+
+	// Async queues log and error lines in a buffer of BufferSize writes.
+	Async      bool
+	BufferSize int
+
+	// SampleEvery keeps one in N debug messages and MaxLinesPerSecond caps
+	// bursts; what they drop is reported as a "suppressed N messages" line.
+	SampleEvery       int
+	MaxLinesPerSecond int
+
+	// Syslog (or OPEN_RUNTIMES_LOGS_SYSLOG), such as "udp://127.0.0.1:514",
+	// sends streams without a writer to syslog instead of files in Dir, over
+	// one connection per process.
+	Syslog string
+
+	// MaxMessageBytes caps a single message, 64KB by default and off when negative.
+	MaxMessageBytes int
+
+	// ConsoleOnly skips log files, for development mode where everything is
+	// mirrored to the terminal anyway.
+	ConsoleOnly bool
+}
+
+func (o LoggerOptions) logsDir() string {
+	if o.Dir != "" {
+		return o.Dir
+	}
+
+	if dir := os.Getenv("OPEN_RUNTIMES_LOGS_DIR"); dir != "" {
+		return dir
+	}
+
+	return LOGS_DIR_DEFAULT
+}
+
+func (l *Logger) prepareWriters(options LoggerOptions) error {
+	dir := options.logsDir()
+
+	var syslogConn *syslogConnection
+	if address := options.syslogAddress(); address != "" {
+		conn, err := sharedSyslog(address)
+		if err != nil {
+			return errors.New("could not connect to syslog")
+		}
+		syslogConn = conn
+	}
+
+	open := func(writer io.Writer, suffix string, severity int) (io.Writer, *os.File, error) {
+		if writer != nil {
+			return writer, nil, nil
+		}
+
+		if options.ConsoleOnly {
+			return io.Discard, nil, nil
+		}
+
+		if syslogConn != nil {
+			forwarder := newSyslogWriter(syslogConn, severity, l.Id)
+			l.closers = append(l.closers, forwarder)
+			return forwarder, nil, nil
+		}
+
+		file, err := os.OpenFile(filepath.Join(dir, l.Id+suffix), os.O_APPEND|os.O_WRONLY|os.O_CREATE, 0644)
+		if err != nil {
+			return nil, nil, errors.New("could not prepare log file")
+		}
+
+		return file, file, nil
+	}
+
+	var err error
+
+	l.writerLogs, l.StreamLogs, err = open(options.LogsWriter, "_logs.log", SYSLOG_SEVERITY_INFO)
+	if err != nil {
+		return err
+	}
+	l.writerLogs = l.wrapWriter(l.writerLogs, l.StreamLogs, options)
+
+	l.writerErrors, l.StreamErrors, err = open(options.ErrorsWriter, "_errors.log", SYSLOG_SEVERITY_ERROR)
+	if err != nil {
+		return err
+	}
+	l.writerErrors = l.wrapWriter(l.writerErrors, l.StreamErrors, options)
+
+	// The audit stream is never capped: truncating it would break its hash chain.
+
+	l.writerAudit, l.StreamAudit, err = open(options.AuditWriter, "_audit.log", SYSLOG_SEVERITY_NOTICE)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// wrapWriter applies the size cap and async buffering to a stream, and
+// remembers the wrappers so End can close them outermost first.
+func (l *Logger) wrapWriter(writer io.Writer, file *os.File, options LoggerOptions) io.Writer {
+	writer = newLimitedWriter(writer, file, options)
+	if limited, ok := writer.(*limitedWriter); ok {
+		l.closers = append([]io.Closer{limited}, l.closers...)
+	}
+
+	if options.Async {
+		buffered := newAsyncWriter(writer, options)
+		l.closers = append([]io.Closer{buffered}, l.closers...)
+		writer = buffered
+	}
+
+	return writer
+}
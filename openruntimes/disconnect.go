@@ -0,0 +1,47 @@
+package openruntimes
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrClientDisconnected is the cause of the execution context's cancellation
+// when the parent context, the incoming HTTP request's in ServeHTTP, is
+// cancelled before the handler returns.
+var ErrClientDisconnected = errors.New("client disconnected")
+
+// withDisconnect derives a context that is cancelled with
+// ErrClientDisconnected when parent is cancelled, keeping parent's values
+// and deadline.
+func withDisconnect(parent context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancelCause(context.WithoutCancel(parent))
+
+	stop := context.AfterFunc(parent, func() {
+		if errors.Is(parent.Err(), context.DeadlineExceeded) {
+			cancel(context.Cause(parent))
+			return
+		}
+		cancel(ErrClientDisconnected)
+	})
+
+	if deadline, ok := parent.Deadline(); ok {
+		var cancelDeadline context.CancelFunc
+		ctx, cancelDeadline = context.WithDeadline(ctx, deadline)
+
+		return ctx, func() {
+			stop()
+			cancelDeadline()
+			cancel(context.Canceled)
+		}
+	}
+
+	return ctx, func() {
+		stop()
+		cancel(context.Canceled)
+	}
+}
+
+// Disconnected reports whether the client went away during the execution.
+func (c *Context) Disconnected() bool {
+	return errors.Is(context.Cause(c.Context()), ErrClientDisconnected)
+}
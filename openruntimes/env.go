@@ -0,0 +1,198 @@
+package openruntimes
+
+import (
+	"errors"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Environment reads typed configuration from environment variables. Values
+// that are missing or fail to parse fall back to the given default.
+type Environment struct{}
+
+func Env() Environment {
+	return Environment{}
+}
+
+func (Environment) String(key string, def string) string {
+	if value, ok := os.LookupEnv(key); ok {
+		return value
+	}
+	return def
+}
+
+func (Environment) Int(key string, def int) int {
+	value, err := strconv.Atoi(strings.TrimSpace(os.Getenv(key)))
+	if err != nil {
+		return def
+	}
+	return value
+}
+
+func (Environment) Bool(key string, def bool) bool {
+	value, err := strconv.ParseBool(strings.TrimSpace(os.Getenv(key)))
+	if err != nil {
+		return def
+	}
+	return value
+}
+
+func (Environment) Duration(key string, def time.Duration) time.Duration {
+	value, err := time.ParseDuration(strings.TrimSpace(os.Getenv(key)))
+	if err != nil {
+		return def
+	}
+	return value
+}
+
+func (Environment) Required(key string) (string, error) {
+	value, ok := os.LookupEnv(key)
+	if !ok || value == "" {
+		return "", FieldError{Field: key, Message: "environment variable is required"}
+	}
+	return value, nil
+}
+
+// LoadEnv fills the fields of the struct cfg points to from the variables
+// named in their `env` tags, for example:
+//
+//	Port    int           `env:"PORT" default:"3000"`
+//	Secret  string        `env:"API_SECRET,required"`
+//	Timeout time.Duration `env:"TIMEOUT" default:"5s"`
+//
+// Strings, booleans, numbers, durations and comma separated slices are
+// supported. Every problem is reported, as a *MultiError of FieldErrors.
+func LoadEnv(cfg any) error {
+	target := reflect.ValueOf(cfg)
+	if target.Kind() != reflect.Pointer || target.IsNil() || target.Elem().Kind() != reflect.Struct {
+		return errors.New("LoadEnv expects a pointer to a struct")
+	}
+
+	target = target.Elem()
+	problems := &MultiError{}
+
+	for i := 0; i < target.NumField(); i++ {
+		field := target.Type().Field(i)
+
+		tag, ok := field.Tag.Lookup("env")
+		if !ok || tag == "-" || !field.IsExported() {
+			continue
+		}
+
+		name, options, _ := strings.Cut(tag, ",")
+		required := options == "required"
+
+		raw, found := os.LookupEnv(name)
+		if !found || raw == "" {
+			if required {
+				problems.Add(FieldError{Field: name, Message: "environment variable is required"})
+				continue
+			}
+
+			raw, found = field.Tag.Lookup("default")
+			if !found {
+				continue
+			}
+		}
+
+		if err := assignString(target.Field(i), raw); err != nil {
+			problems.Add(FieldError{Field: name, Message: err.Error()})
+		}
+	}
+
+	return problems.ErrorOrNil()
+}
+
+var (
+	durationType = reflect.TypeOf(time.Duration(0))
+	timeType     = reflect.TypeOf(time.Time{})
+)
+
+// assignString parses raw into value according to its type.
+func assignString(value reflect.Value, raw string) error {
+	if value.Kind() == reflect.Pointer {
+		if value.IsNil() {
+			value.Set(reflect.New(value.Type().Elem()))
+		}
+		return assignString(value.Elem(), raw)
+	}
+
+	if value.Type() == durationType {
+		duration, err := time.ParseDuration(strings.TrimSpace(raw))
+		if err != nil {
+			return errors.New("invalid duration " + strconv.Quote(raw))
+		}
+		value.SetInt(int64(duration))
+		return nil
+	}
+
+	if value.Type() == timeType {
+		parsed, err := parseTime(strings.TrimSpace(raw))
+		if err != nil {
+			return errors.New("invalid time " + strconv.Quote(raw))
+		}
+		value.Set(reflect.ValueOf(parsed))
+		return nil
+	}
+
+	switch value.Kind() {
+	case reflect.String:
+		value.SetString(raw)
+	case reflect.Bool:
+		parsed, err := strconv.ParseBool(strings.TrimSpace(raw))
+		if err != nil {
+			return errors.New("invalid boolean " + strconv.Quote(raw))
+		}
+		value.SetBool(parsed)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		parsed, err := strconv.ParseInt(strings.TrimSpace(raw), 10, value.Type().Bits())
+		if err != nil {
+			return errors.New("invalid integer " + strconv.Quote(raw))
+		}
+		value.SetInt(parsed)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		parsed, err := strconv.ParseUint(strings.TrimSpace(raw), 10, value.Type().Bits())
+		if err != nil {
+			return errors.New("invalid unsigned integer " + strconv.Quote(raw))
+		}
+		value.SetUint(parsed)
+	case reflect.Float32, reflect.Float64:
+		parsed, err := strconv.ParseFloat(strings.TrimSpace(raw), value.Type().Bits())
+		if err != nil {
+			return errors.New("invalid number " + strconv.Quote(raw))
+		}
+		value.SetFloat(parsed)
+	case reflect.Slice:
+		parts := strings.Split(raw, ",")
+		slice := reflect.MakeSlice(value.Type(), len(parts), len(parts))
+		for i, part := range parts {
+			if err := assignString(slice.Index(i), strings.TrimSpace(part)); err != nil {
+				return err
+			}
+		}
+		value.Set(slice)
+	default:
+		return errors.New("unsupported type " + value.Type().String())
+	}
+
+	return nil
+}
+
+// parseTime accepts RFC 3339 timestamps, plain dates and Unix seconds.
+func parseTime(raw string) (time.Time, error) {
+	if parsed, err := time.Parse(time.RFC3339Nano, raw); err == nil {
+		return parsed, nil
+	}
+	if parsed, err := time.Parse(time.DateOnly, raw); err == nil {
+		return parsed, nil
+	}
+
+	seconds, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Unix(seconds, 0).UTC(), nil
+}
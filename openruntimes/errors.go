@@ -0,0 +1,126 @@
+package openruntimes
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+type FieldError struct {
+	Field   string
+	Message string
+}
+
+func (e FieldError) Error() string {
+	if e.Field == "" {
+		return e.Message
+	}
+	return e.Field + ": " + e.Message
+}
+
+// MultiError is safe to fill from several goroutines.
+type MultiError struct {
+	mutex  sync.Mutex
+	errors []error
+}
+
+func (m *MultiError) Add(err error) {
+	if err == nil {
+		return
+	}
+
+	var nested *MultiError
+	if errors.As(err, &nested) && nested != m {
+		for _, inner := range nested.Errors() {
+			m.Add(inner)
+		}
+		return
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.errors = append(m.errors, err)
+}
+
+func (m *MultiError) Errors() []error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return append([]error{}, m.errors...)
+}
+
+func (m *MultiError) Len() int {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return len(m.errors)
+}
+
+func (m *MultiError) ErrorOrNil() error {
+	if m == nil || m.Len() == 0 {
+		return nil
+	}
+	return m
+}
+
+func (m *MultiError) Error() string {
+	errs := m.Errors()
+
+	messages := []string{}
+	for _, err := range errs {
+		messages = append(messages, err.Error())
+	}
+
+	return strconv.Itoa(len(errs)) + " errors occurred: " + strings.Join(messages, "; ")
+}
+
+func (m *MultiError) Unwrap() []error {
+	return m.Errors()
+}
+
+func (m *MultiError) MarshalJSON() ([]byte, error) {
+	items := []map[string]string{}
+
+	for _, err := range m.Errors() {
+		item := map[string]string{"message": err.Error()}
+
+		var fieldError FieldError
+		if errors.As(err, &fieldError) {
+			item["message"] = fieldError.Message
+			if fieldError.Field != "" {
+				item["field"] = fieldError.Field
+			}
+		}
+
+		items = append(items, item)
+	}
+
+	return json.Marshal(map[string]interface{}{
+		"errors": items,
+	})
+}
+
+type ErrorHandler func(*Context) (Response, error)
+
+// HandleErrors adapts an error-returning handler: a MultiError becomes a 400
+// listing every failure, any other error an opaque 500. Errors are always logged.
+func HandleErrors(handler ErrorHandler) Handler {
+	return func(c *Context) Response {
+		response, err := handler(c)
+		if err == nil {
+			return response
+		}
+
+		c.Error(err.Error())
+
+		var multiError *MultiError
+		if errors.As(err, &multiError) {
+			return c.Res.Json(multiError, c.Res.WithStatusCode(400))
+		}
+
+		return c.Res.InternalError(err)
+	}
+}
@@ -0,0 +1,58 @@
+package openruntimes
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+func ComputeETag(body []byte) string {
+	sum := sha256.Sum256(body)
+	return "\"" + hex.EncodeToString(sum[:16]) + "\""
+}
+
+// WithETag sets a strong ETag computed from a buffered body.
+func (r ContextResponse) WithETag() ResponseOption {
+	return func(o *Response) {
+		o.enabledSetters["ETag"] = true
+	}
+}
+
+// Cached tags a buffered response with a strong ETag (unless it already has one)
+// and turns it into a 304 when the request's If-None-Match matches.
+func (c *Context) Cached(response Response) Response {
+	if response.IsStream() || response.StatusCode < 200 || response.StatusCode > 299 {
+		return response
+	}
+
+	headers := cloneStringMap(response.Headers)
+	if headers == nil {
+		headers = map[string]string{}
+	}
+
+	etag, _ := headerGet(headers, "etag")
+	if etag == "" {
+		etag = ComputeETag(response.Body)
+		headerSet(headers, "etag", etag)
+	}
+	response.Headers = headers
+
+	method := strings.ToUpper(c.Req.Method)
+	if method != "GET" && method != "HEAD" {
+		return response
+	}
+
+	if !MatchETag(c.Req.IfNoneMatch(), etag, true) {
+		return response
+	}
+
+	notModified := map[string]string{}
+	for key, value := range headers {
+		switch strings.ToLower(key) {
+		case "etag", "cache-control", "content-location", "date", "expires", "vary", "last-modified":
+			notModified[key] = value
+		}
+	}
+
+	return c.Res.NotModified(c.Res.WithHeaders(notModified))
+}
@@ -0,0 +1,75 @@
+package openruntimes
+
+import (
+	"os"
+	"strconv"
+	"strings"
+)
+
+const (
+	TRIGGER_HTTP     = "http"
+	TRIGGER_SCHEDULE = "schedule"
+	TRIGGER_EVENT    = "event"
+)
+
+// Execution describes the function and deployment an execution belongs to.
+// Empty fields mean the executor did not provide them; MemoryLimit is in MB.
+type Execution struct {
+	Id             string `json:"id"`
+	FunctionId     string `json:"functionId"`
+	DeploymentId   string `json:"deploymentId"`
+	RuntimeVersion string `json:"runtimeVersion"`
+	Trigger        string `json:"trigger"`
+	Region         string `json:"region"`
+	MemoryLimit    int    `json:"memoryLimit"`
+}
+
+// lookupMetadata returns the first non-empty request header, then the first
+// non-empty environment variable.
+func lookupMetadata(req ContextRequest, headers []string, env []string) string {
+	for _, header := range headers {
+		if value := strings.TrimSpace(req.Header(header)); value != "" {
+			return value
+		}
+	}
+
+	for _, name := range env {
+		if value := strings.TrimSpace(os.Getenv(name)); value != "" {
+			return value
+		}
+	}
+
+	return ""
+}
+
+// executionFromRequest reads the metadata from the executor headers, which
+// Invoke strips before the handler runs, and from the environment.
+func executionFromRequest(req ContextRequest, id string) Execution {
+	execution := Execution{
+		Id:             id,
+		FunctionId:     lookupMetadata(req, []string{"x-open-runtimes-function-id"}, []string{"OPEN_RUNTIMES_FUNCTION_ID", "APPWRITE_FUNCTION_ID"}),
+		DeploymentId:   lookupMetadata(req, []string{"x-open-runtimes-deployment-id"}, []string{"OPEN_RUNTIMES_DEPLOYMENT_ID", "APPWRITE_FUNCTION_DEPLOYMENT"}),
+		RuntimeVersion: lookupMetadata(req, nil, []string{"OPEN_RUNTIMES_RUNTIME_VERSION", "APPWRITE_FUNCTION_RUNTIME_VERSION"}),
+		Trigger:        strings.ToLower(lookupMetadata(req, []string{"x-open-runtimes-trigger", "x-appwrite-trigger"}, nil)),
+		Region:         lookupMetadata(req, []string{"x-open-runtimes-region"}, []string{"OPEN_RUNTIMES_REGION", "APPWRITE_REGION"}),
+	}
+
+	if execution.Trigger == "" {
+		execution.Trigger = TRIGGER_HTTP
+	}
+
+	memory := lookupMetadata(req, nil, []string{"OPEN_RUNTIMES_MEMORY", "APPWRITE_FUNCTION_MEMORY"})
+	if limit, err := strconv.Atoi(memory); err == nil {
+		execution.MemoryLimit = limit
+	}
+
+	return execution
+}
+
+func (c *Context) Execution() Execution {
+	if c.execution == nil {
+		execution := executionFromRequest(c.Req, c.logger.Id)
+		c.execution = &execution
+	}
+	return *c.execution
+}
@@ -0,0 +1,81 @@
+package openruntimes
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+func (c *Context) LogWith(message string, fields map[string]any) {
+	c.logger.WriteWith(message, fields, LOGGER_TYPE_LOG)
+}
+
+func (c *Context) ErrorWith(message string, fields map[string]any) {
+	c.logger.WriteWith(message, fields, LOGGER_TYPE_ERROR)
+}
+
+// WriteWith emits one record made of a message and fields, rendered as
+// logfmt-style key=value pairs sorted by key.
+func (l *Logger) WriteWith(message string, fields map[string]any, xtype string) {
+	defer l.lock()()
+
+	if !l.IsLevelEnabled(xtype) || !l.sample(xtype) {
+		return
+	}
+
+	l.writeRecord(xtype, message, fields, "\n")
+}
+
+func formatFields(fields map[string]any) string {
+	if len(fields) == 0 {
+		return ""
+	}
+
+	keys := []string{}
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	formatted := strings.Builder{}
+	for _, key := range keys {
+		formatted.WriteString(" ")
+		formatted.WriteString(key)
+		formatted.WriteString("=")
+		formatted.WriteString(formatFieldValue(fields[key]))
+	}
+
+	return formatted.String()
+}
+
+func formatFieldValue(value any) string {
+	var text string
+
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case string:
+		text = v
+	case error:
+		text = v.Error()
+	case fmt.Stringer:
+		text = v.String()
+	case bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return fmt.Sprint(v)
+	default:
+		encoded, err := json.Marshal(v)
+		if err != nil {
+			text = fmt.Sprintf("%#v", v)
+		} else {
+			text = string(encoded)
+		}
+	}
+
+	if text == "" || strings.ContainsAny(text, " =\"\t\r\n") {
+		return strconv.Quote(text)
+	}
+
+	return text
+}
@@ -0,0 +1,87 @@
+package openruntimes
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/open-runtimes/types-for-go/v4/mimetypes"
+)
+
+func (r ContextResponse) File(path string, optionalSetters ...ResponseOption) Response {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return r.Text("File not found.", r.WithStatusCode(404))
+		}
+		return r.Text("Error reading file.", r.WithStatusCode(500))
+	}
+
+	optionalSetters = append([]ResponseOption{r.WithHeaders(map[string]string{
+		"content-type":   mimetypes.TypeByFilename(filepath.Base(path)),
+		"content-length": strconv.Itoa(len(content)),
+	})}, optionalSetters...)
+
+	return r.Binary(content, optionalSetters...)
+}
+
+func (r ContextResponse) Download(bytes []byte, filename string, optionalSetters ...ResponseOption) Response {
+	optionalSetters = append([]ResponseOption{r.WithHeaders(map[string]string{
+		"content-type":        mimetypes.TypeByFilename(filename),
+		"content-length":      strconv.Itoa(len(bytes)),
+		"content-disposition": contentDisposition("attachment", filename),
+	})}, optionalSetters...)
+
+	return r.Binary(bytes, optionalSetters...)
+}
+
+func (r ContextResponse) WithAttachment(filename string) ResponseOption {
+	return func(o *Response) {
+		o.mergeHeaders(map[string]string{
+			"content-disposition": contentDisposition("attachment", filename),
+		})
+	}
+}
+
+// contentDisposition adds an RFC 5987 filename* parameter whenever the
+// filename cannot be represented safely as a quoted ASCII string.
+func contentDisposition(disposition string, filename string) string {
+	if filename == "" {
+		return disposition
+	}
+
+	fallback := strings.Map(func(c rune) rune {
+		if c < 0x20 || c > 0x7e || c == '"' || c == '\\' {
+			return '_'
+		}
+		return c
+	}, filename)
+
+	header := disposition + "; filename=\"" + fallback + "\""
+	if fallback != filename {
+		header += "; filename*=UTF-8''" + encodeExtValue(filename)
+	}
+
+	return header
+}
+
+func encodeExtValue(value string) string {
+	const hex = "0123456789ABCDEF"
+	encoded := strings.Builder{}
+
+	for _, c := range []byte(value) {
+		if (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9') || strings.IndexByte("!#$&+-.^_`|~", c) != -1 {
+			encoded.WriteByte(c)
+			continue
+		}
+
+		encoded.WriteByte('%')
+		encoded.WriteByte(hex[c>>4])
+		encoded.WriteByte(hex[c&0x0f])
+	}
+
+	return encoded.String()
+}
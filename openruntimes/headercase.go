@@ -0,0 +1,95 @@
+package openruntimes
+
+import (
+	"net/textproto"
+	"strings"
+)
+
+const HEADER_CASE_LOWERCASE = "lowercase"
+const HEADER_CASE_CANONICAL = "canonical"
+
+// HeaderCase decides how header keys are stored on requests and responses.
+// Keys differing only in case are always collapsed into one.
+var HeaderCase = HEADER_CASE_LOWERCASE
+
+func NormalizeHeaderKey(key string) string {
+	if HeaderCase == HEADER_CASE_CANONICAL {
+		return textproto.CanonicalMIMEHeaderKey(key)
+	}
+	return strings.ToLower(key)
+}
+
+func normalizeHeaderMap(headers map[string]string) map[string]string {
+	normalized := make(map[string]string, len(headers))
+	for key, value := range headers {
+		normalized[NormalizeHeaderKey(key)] = value
+	}
+	return normalized
+}
+
+func headerGet(headers map[string]string, key string) (string, bool) {
+	if value, ok := headers[NormalizeHeaderKey(key)]; ok {
+		return value, true
+	}
+
+	for name, value := range headers {
+		if strings.EqualFold(name, key) {
+			return value, true
+		}
+	}
+
+	return "", false
+}
+
+func headerSet(headers map[string]string, key string, value string) {
+	headerDelete(headers, key)
+	headers[NormalizeHeaderKey(key)] = value
+}
+
+func headerDelete(headers map[string]string, key string) {
+	for name := range headers {
+		if strings.EqualFold(name, key) {
+			delete(headers, name)
+		}
+	}
+}
+
+// NormalizeHeaders rewrites Headers using HeaderCase; runtime servers call it
+// after filling the map so handlers see one consistent casing.
+func (r *ContextRequest) NormalizeHeaders() {
+	r.Headers = normalizeHeaderMap(r.Headers)
+
+	if r.headerValues != nil {
+		values := make(map[string][]string, len(r.headerValues))
+		for key, entries := range r.headerValues {
+			normalized := NormalizeHeaderKey(key)
+			values[normalized] = append(values[normalized], entries...)
+		}
+		r.headerValues = values
+	}
+}
+
+// withVary adds field to the Vary header the other options built up.
+func withVary(field string) ResponseOption {
+	return func(o *Response) {
+		o.mergeHeaders(nil)
+		addVary(o.Headers, field)
+	}
+}
+
+// addVary appends field to the Vary header unless it is already listed.
+func addVary(headers map[string]string, field string) {
+	vary, _ := headerGet(headers, "vary")
+	if vary == "" {
+		headerSet(headers, "vary", field)
+		return
+	}
+
+	for _, existing := range strings.Split(vary, ",") {
+		if strings.EqualFold(strings.TrimSpace(existing), field) {
+			return
+		}
+	}
+
+	headerSet(headers, "vary", vary+", "+field)
+}
@@ -0,0 +1,86 @@
+package openruntimes
+
+import (
+	"sort"
+	"strings"
+)
+
+// AddHeader appends a value for key instead of replacing it, for headers such
+// as Link or Vary that may legitimately repeat.
+func (r ContextResponse) AddHeader(key string, value string) ResponseOption {
+	return func(o *Response) {
+		o.AddHeader(key, value)
+	}
+}
+
+// WithTrailer declares a trailer sent after the body. Handlers streaming a body
+// may update Response.Trailers until the reader is drained, for example with a checksum.
+func (r ContextResponse) WithTrailer(key string, value string) ResponseOption {
+	return func(o *Response) {
+		if o.Trailers == nil {
+			o.Trailers = map[string]string{}
+		}
+		headerSet(o.Trailers, key, value)
+	}
+}
+
+func (r *Response) AddHeader(key string, value string) {
+	if r.HeaderValues == nil {
+		r.HeaderValues = map[string][]string{}
+	}
+
+	key = NormalizeHeaderKey(key)
+	r.HeaderValues[key] = append(r.HeaderValues[key], value)
+}
+
+// Values returns every value of a header: the one from Headers first, then
+// those added with AddHeader and, for set-cookie, the serialized Cookies.
+func (r Response) Values(key string) []string {
+	values := []string{}
+
+	for name, value := range r.Headers {
+		if strings.EqualFold(name, key) {
+			values = append(values, value)
+		}
+	}
+
+	for name, added := range r.HeaderValues {
+		if strings.EqualFold(name, key) {
+			values = append(values, added...)
+		}
+	}
+
+	if strings.EqualFold(key, "set-cookie") {
+		values = append(values, r.SetCookieHeaders()...)
+	}
+
+	return values
+}
+
+func (r Response) AllHeaders() map[string][]string {
+	names := map[string]bool{}
+	for name := range r.Headers {
+		names[NormalizeHeaderKey(name)] = true
+	}
+	for name := range r.HeaderValues {
+		names[NormalizeHeaderKey(name)] = true
+	}
+	if len(r.Cookies) > 0 {
+		names[NormalizeHeaderKey("set-cookie")] = true
+	}
+
+	keys := []string{}
+	for name := range names {
+		keys = append(keys, name)
+	}
+	sort.Strings(keys)
+
+	headers := map[string][]string{}
+	for _, name := range keys {
+		if values := r.Values(name); len(values) > 0 {
+			headers[name] = values
+		}
+	}
+
+	return headers
+}
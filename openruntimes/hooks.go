@@ -0,0 +1,79 @@
+package openruntimes
+
+import (
+	"sync"
+)
+
+var (
+	hooksMutex  sync.RWMutex
+	startHooks  []func(*Context)
+	finishHooks []func(*Context, Response)
+	panicHooks  []func(*Context, any)
+)
+
+// OnStart registers a hook the Server runs before every handler, in
+// registration order.
+func OnStart(hook func(*Context)) {
+	hooksMutex.Lock()
+	defer hooksMutex.Unlock()
+
+	startHooks = append(startHooks, hook)
+}
+
+// OnFinish registers a hook that sees every response, including the 500 sent
+// after a panic.
+func OnFinish(hook func(*Context, Response)) {
+	hooksMutex.Lock()
+	defer hooksMutex.Unlock()
+
+	finishHooks = append(finishHooks, hook)
+}
+
+// OnPanic registers a hook that receives what a panicking handler or start
+// hook recovered with. A panic inside the hook itself is ignored.
+func OnPanic(hook func(*Context, any)) {
+	hooksMutex.Lock()
+	defer hooksMutex.Unlock()
+
+	panicHooks = append(panicHooks, hook)
+}
+
+func runStartHooks(context *Context) {
+	hooksMutex.RLock()
+	hooks := append([]func(*Context){}, startHooks...)
+	hooksMutex.RUnlock()
+
+	for _, hook := range hooks {
+		hook(context)
+	}
+}
+
+func runFinishHooks(context *Context, response Response) {
+	hooksMutex.RLock()
+	hooks := append([]func(*Context, Response){}, finishHooks...)
+	hooksMutex.RUnlock()
+
+	for _, hook := range hooks {
+		func() {
+			defer func() {
+				if recovered := recover(); recovered != nil {
+					context.Error("OnFinish hook panicked:", recovered)
+				}
+			}()
+			hook(context, response)
+		}()
+	}
+}
+
+func runPanicHooks(context *Context, recovered any) {
+	hooksMutex.RLock()
+	hooks := append([]func(*Context, any){}, panicHooks...)
+	hooksMutex.RUnlock()
+
+	for _, hook := range hooks {
+		func() {
+			defer func() { recover() }()
+			hook(context, recovered)
+		}()
+	}
+}
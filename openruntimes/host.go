@@ -0,0 +1,186 @@
+package openruntimes
+
+import (
+	"errors"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+func DefaultPort(scheme string) int {
+	switch strings.ToLower(scheme) {
+	case "https", "wss":
+		return 443
+	case "http", "ws":
+		return 80
+	}
+	return 0
+}
+
+// ParseHost splits a Host header value into hostname and port, handling
+// bracketed IPv6 literals. Port is 0 when the host does not specify one.
+func ParseHost(host string) (string, int, error) {
+	host = strings.TrimSpace(host)
+	if host == "" {
+		return "", 0, errors.New("host is empty")
+	}
+
+	if strings.HasPrefix(host, "[") {
+		end := strings.Index(host, "]")
+		if end == -1 {
+			return "", 0, errors.New("host has unterminated IPv6 literal")
+		}
+
+		hostname := host[1:end]
+		if net.ParseIP(hostname) == nil {
+			return "", 0, errors.New("host has invalid IPv6 literal")
+		}
+
+		rest := host[end+1:]
+		if rest == "" {
+			return hostname, 0, nil
+		}
+		if !strings.HasPrefix(rest, ":") {
+			return "", 0, errors.New("host has invalid characters after IPv6 literal")
+		}
+
+		port, err := parsePort(rest[1:])
+		if err != nil {
+			return "", 0, err
+		}
+		return hostname, port, nil
+	}
+
+	if strings.Count(host, ":") > 1 {
+		if net.ParseIP(host) == nil {
+			return "", 0, errors.New("host is invalid")
+		}
+		return host, 0, nil
+	}
+
+	hostname, portString, found := strings.Cut(host, ":")
+	if hostname == "" {
+		return "", 0, errors.New("host is missing a hostname")
+	}
+	if !isRegName(hostname) {
+		return "", 0, errors.New("host has invalid characters")
+	}
+	if !found {
+		return strings.ToLower(hostname), 0, nil
+	}
+
+	port, err := parsePort(portString)
+	if err != nil {
+		return "", 0, err
+	}
+
+	return strings.ToLower(hostname), port, nil
+}
+
+// isRegName reports whether hostname is an RFC 3986 reg-name: unreserved
+// characters, sub-delims and percent-encoded octets, which covers IPv4
+// addresses too.
+func isRegName(hostname string) bool {
+	for i := 0; i < len(hostname); i++ {
+		c := hostname[i]
+
+		switch {
+		case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9':
+		case strings.IndexByte("-._~!$&'()*+,;=", c) != -1:
+		case c == '%' && i+2 < len(hostname) && isHex(hostname[i+1]) && isHex(hostname[i+2]):
+			i += 2
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func isHex(c byte) bool {
+	return '0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F'
+}
+
+func parsePort(port string) (int, error) {
+	value, err := strconv.Atoi(port)
+	if err != nil || value < 1 || value > 65535 {
+		return 0, errors.New("host has invalid port")
+	}
+	return value, nil
+}
+
+// SetHost stores the host and reconciles Port with it, falling back to the
+// scheme default when the host carries no explicit port.
+func (r *ContextRequest) SetHost(host string) error {
+	hostname, port, err := ParseHost(host)
+	if err != nil {
+		return err
+	}
+
+	if port == 0 {
+		port = DefaultPort(r.Scheme)
+	}
+
+	if strings.Contains(hostname, ":") {
+		r.Host = "[" + hostname + "]"
+	} else {
+		r.Host = hostname
+	}
+	r.Port = port
+
+	return nil
+}
+
+func (r ContextRequest) Hostname() string {
+	hostname, _, err := ParseHost(r.Host)
+	if err != nil {
+		return ""
+	}
+	return hostname
+}
+
+func (r ContextRequest) EffectivePort() int {
+	if _, port, err := ParseHost(r.Host); err == nil && port != 0 {
+		return port
+	}
+
+	if r.Port != 0 {
+		return r.Port
+	}
+
+	return DefaultPort(r.Scheme)
+}
+
+// URL omits the port when it is the default for the scheme.
+func (r ContextRequest) URL() *url.URL {
+	scheme := strings.ToLower(r.Scheme)
+	if scheme == "" {
+		scheme = "http"
+	}
+
+	host := r.Hostname()
+	if strings.Contains(host, ":") {
+		host = "[" + host + "]"
+	}
+
+	port := r.EffectivePort()
+	if port != 0 && port != DefaultPort(scheme) {
+		host += ":" + strconv.Itoa(port)
+	}
+
+	path := r.Path
+	if path == "" {
+		path = "/"
+	}
+
+	return &url.URL{
+		Scheme:   scheme,
+		Host:     host,
+		Path:     path,
+		RawQuery: strings.TrimPrefix(r.QueryString, "?"),
+	}
+}
+
+func (r ContextRequest) FullURL() string {
+	return r.URL().String()
+}
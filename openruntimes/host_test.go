@@ -0,0 +1,54 @@
+package openruntimes
+
+import "testing"
+
+func TestParseHost(t *testing.T) {
+	tests := []struct {
+		host     string
+		hostname string
+		port     int
+		invalid  bool
+	}{
+		{host: "Example.COM", hostname: "example.com"},
+		{host: "example.com:8080", hostname: "example.com", port: 8080},
+		{host: "127.0.0.1:80", hostname: "127.0.0.1", port: 80},
+		{host: "[2001:db8::1]:443", hostname: "2001:db8::1", port: 443},
+		{host: "my_host.local", hostname: "my_host.local"},
+		{host: "caf%C3%A9.example", hostname: "caf%c3%a9.example"},
+		{host: "", invalid: true},
+		{host: "exa mple.com", invalid: true},
+		{host: "example.com/path", invalid: true},
+		{host: "user@example.com", invalid: true},
+		{host: "example.com?x", invalid: true},
+		{host: "bad%zz", invalid: true},
+		{host: "example.com:0", invalid: true},
+		{host: "[not-ip]", invalid: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.host, func(t *testing.T) {
+			hostname, port, err := ParseHost(test.host)
+			if test.invalid {
+				if err == nil {
+					t.Fatalf("ParseHost() = %q, %d, want an error", hostname, port)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("ParseHost() error = %v", err)
+			}
+			if hostname != test.hostname || port != test.port {
+				t.Errorf("ParseHost() = %q, %d, want %q, %d", hostname, port, test.hostname, test.port)
+			}
+		})
+	}
+}
+
+func TestFullURLDropsInvalidHost(t *testing.T) {
+	req := ContextRequest{Scheme: "https", Host: "evil.com/x?", Path: "/a"}
+
+	if got := req.FullURL(); got != "https:///a" {
+		t.Errorf("FullURL() = %q, want the invalid host left out", got)
+	}
+}
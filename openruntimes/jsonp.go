@@ -0,0 +1,54 @@
+package openruntimes
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+)
+
+var jsonpCallbackPattern = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*(\.[A-Za-z_$][A-Za-z0-9_$]*)*$`)
+
+var jsonpReservedWords = map[string]bool{
+	"break": true, "case": true, "catch": true, "class": true, "const": true, "continue": true,
+	"debugger": true, "default": true, "delete": true, "do": true, "else": true, "export": true,
+	"extends": true, "false": true, "finally": true, "for": true, "function": true, "if": true,
+	"import": true, "in": true, "instanceof": true, "new": true, "null": true, "return": true,
+	"super": true, "switch": true, "this": true, "throw": true, "true": true, "try": true,
+	"typeof": true, "var": true, "void": true, "while": true, "with": true, "yield": true,
+	"let": true, "static": true, "enum": true, "await": true, "eval": true, "arguments": true,
+}
+
+func IsValidJsonpCallback(callback string) bool {
+	if len(callback) == 0 || len(callback) > 128 || !jsonpCallbackPattern.MatchString(callback) {
+		return false
+	}
+
+	for _, part := range strings.Split(callback, ".") {
+		if jsonpReservedWords[part] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Jsonp wraps v in a call to callback. The leading comment and nosniff header
+// guard against content-sniffing attacks such as Rosetta Flash.
+func (r ContextResponse) Jsonp(callback string, v any, optionalSetters ...ResponseOption) Response {
+	if !IsValidJsonpCallback(callback) {
+		return r.BadRequest("Invalid JSONP callback.")
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		optionalSetters = append(optionalSetters, r.WithStatusCode(500))
+		return r.Text("Error encoding JSON.", optionalSetters...)
+	}
+
+	optionalSetters = append(optionalSetters, r.WithHeaders(map[string]string{
+		"content-type":           "text/javascript; charset=utf-8",
+		"x-content-type-options": "nosniff",
+	}))
+
+	return r.Text("/**/ typeof "+callback+" === 'function' && "+callback+"("+string(data)+");", optionalSetters...)
+}
@@ -0,0 +1,453 @@
+package openruntimes
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"math"
+	"net/mail"
+	"net/url"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// Compiled schemas are kept for the life of the process, keyed by the hash
+// of their source, so warm invocations skip parsing the same schema again.
+var (
+	schemaMutex     sync.RWMutex
+	compiledSchemas = map[[sha256.Size]byte]*jsonSchema{}
+)
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// jsonSchema is the subset of JSON Schema (draft 2020-12) that
+// ValidateJSONSchema understands. Unknown keywords are ignored.
+type jsonSchema struct {
+	always *bool
+
+	Ref                  string                 `json:"$ref"`
+	Defs                 map[string]*jsonSchema `json:"$defs"`
+	Definitions          map[string]*jsonSchema `json:"definitions"`
+	Type                 schemaTypes            `json:"type"`
+	Enum                 []any                  `json:"enum"`
+	Const                json.RawMessage        `json:"const"`
+	Properties           map[string]*jsonSchema `json:"properties"`
+	Required             []string               `json:"required"`
+	AdditionalProperties *jsonSchema            `json:"additionalProperties"`
+	Items                *jsonSchema            `json:"items"`
+	MinItems             *int                   `json:"minItems"`
+	MaxItems             *int                   `json:"maxItems"`
+	UniqueItems          bool                   `json:"uniqueItems"`
+	MinLength            *int                   `json:"minLength"`
+	MaxLength            *int                   `json:"maxLength"`
+	Pattern              string                 `json:"pattern"`
+	Format               string                 `json:"format"`
+	Minimum              *float64               `json:"minimum"`
+	Maximum              *float64               `json:"maximum"`
+	ExclusiveMinimum     *float64               `json:"exclusiveMinimum"`
+	ExclusiveMaximum     *float64               `json:"exclusiveMaximum"`
+	MultipleOf           *float64               `json:"multipleOf"`
+	AllOf                []*jsonSchema          `json:"allOf"`
+	AnyOf                []*jsonSchema          `json:"anyOf"`
+	OneOf                []*jsonSchema          `json:"oneOf"`
+	Not                  *jsonSchema            `json:"not"`
+
+	ref     *jsonSchema
+	pattern *regexp.Regexp
+	value   any
+}
+
+// schemaTypes accepts "type" as either a single name or a list of names.
+type schemaTypes []string
+
+func (t *schemaTypes) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*t = schemaTypes{single}
+		return nil
+	}
+
+	var several []string
+	if err := json.Unmarshal(data, &several); err != nil {
+		return errors.New("type must be a string or an array of strings")
+	}
+	*t = several
+	return nil
+}
+
+func (s *jsonSchema) UnmarshalJSON(data []byte) error {
+	var always bool
+	if err := json.Unmarshal(data, &always); err == nil {
+		s.always = &always
+		return nil
+	}
+
+	type plain jsonSchema
+	return json.Unmarshal(data, (*plain)(s))
+}
+
+// ValidateJSONSchema checks the JSON body against schema before it is
+// unmarshaled. Violations are returned as ValidationErrors, with fields
+// named by their path in the body ("items[2].name"); a malformed schema or
+// body is reported as a plain error.
+func (r ContextRequest) ValidateJSONSchema(schema []byte) error {
+	compiled, err := loadSchema(schema)
+	if err != nil {
+		return err
+	}
+
+	var body any
+	if err := json.Unmarshal(r.BodyBinary(), &body); err != nil {
+		return errors.New("invalid JSON body: " + err.Error())
+	}
+
+	problems := ValidationErrors{}
+	compiled.validate(body, "", &problems)
+
+	if len(problems) == 0 {
+		return nil
+	}
+	return problems
+}
+
+func loadSchema(schema []byte) (*jsonSchema, error) {
+	key := sha256.Sum256(schema)
+
+	schemaMutex.RLock()
+	compiled, ok := compiledSchemas[key]
+	schemaMutex.RUnlock()
+
+	if ok {
+		return compiled, nil
+	}
+
+	compiled = &jsonSchema{}
+	if err := json.Unmarshal(schema, compiled); err != nil {
+		return nil, errors.New("invalid JSON schema: " + err.Error())
+	}
+	if err := compiled.compile(compiled, map[*jsonSchema]bool{}); err != nil {
+		return nil, errors.New("invalid JSON schema: " + err.Error())
+	}
+
+	schemaMutex.Lock()
+	defer schemaMutex.Unlock()
+
+	compiledSchemas[key] = compiled
+	return compiled, nil
+}
+
+// compile resolves references against root and prepares patterns and
+// constants, so validation does no parsing of its own.
+func (s *jsonSchema) compile(root *jsonSchema, seen map[*jsonSchema]bool) error {
+	if s == nil || seen[s] {
+		return nil
+	}
+	seen[s] = true
+
+	if s.Ref != "" {
+		target, err := root.resolve(s.Ref)
+		if err != nil {
+			return err
+		}
+		s.ref = target
+	}
+
+	if s.Pattern != "" {
+		pattern, err := regexp.Compile(s.Pattern)
+		if err != nil {
+			return errors.New("pattern " + strconv.Quote(s.Pattern) + " does not compile")
+		}
+		s.pattern = pattern
+	}
+
+	if s.Const != nil {
+		if err := json.Unmarshal(s.Const, &s.value); err != nil {
+			return err
+		}
+	}
+
+	children := []*jsonSchema{s.AdditionalProperties, s.Items, s.Not}
+	children = append(children, s.AllOf...)
+	children = append(children, s.AnyOf...)
+	children = append(children, s.OneOf...)
+	for _, group := range []map[string]*jsonSchema{s.Defs, s.Definitions, s.Properties} {
+		for _, child := range group {
+			children = append(children, child)
+		}
+	}
+
+	for _, child := range children {
+		if err := child.compile(root, seen); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// resolve follows the local references schemas use in practice: "#",
+// "#/$defs/name" and "#/definitions/name".
+func (s *jsonSchema) resolve(ref string) (*jsonSchema, error) {
+	if ref == "#" {
+		return s, nil
+	}
+
+	group, name, _ := strings.Cut(strings.TrimPrefix(ref, "#/"), "/")
+	name = strings.ReplaceAll(strings.ReplaceAll(name, "~1", "/"), "~0", "~")
+
+	var target *jsonSchema
+	switch group {
+	case "$defs":
+		target = s.Defs[name]
+	case "definitions":
+		target = s.Definitions[name]
+	}
+
+	if !strings.HasPrefix(ref, "#/") || target == nil {
+		return nil, errors.New("unsupported $ref " + strconv.Quote(ref))
+	}
+	return target, nil
+}
+
+func (s *jsonSchema) validate(value any, path string, problems *ValidationErrors) {
+	fail := func(message string) {
+		*problems = append(*problems, FieldError{Field: path, Message: message})
+	}
+
+	if s.always != nil {
+		if !*s.always {
+			fail("is not allowed")
+		}
+		return
+	}
+
+	if s.ref != nil {
+		s.ref.validate(value, path, problems)
+	}
+
+	if len(s.Type) > 0 && !matchesType(value, s.Type) {
+		fail("must be of type " + strings.Join(s.Type, " or "))
+		return
+	}
+
+	if s.Enum != nil {
+		found := false
+		for _, option := range s.Enum {
+			if reflect.DeepEqual(option, value) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			fail("must be one of the allowed values")
+		}
+	}
+
+	if s.Const != nil && !reflect.DeepEqual(s.value, value) {
+		fail("must be " + string(s.Const))
+	}
+
+	switch typed := value.(type) {
+	case string:
+		s.validateString(typed, fail)
+	case float64:
+		s.validateNumber(typed, fail)
+	case []any:
+		s.validateArray(typed, path, problems, fail)
+	case map[string]any:
+		s.validateObject(typed, path, problems, fail)
+	}
+
+	for _, sub := range s.AllOf {
+		sub.validate(value, path, problems)
+	}
+
+	if len(s.AnyOf) > 0 && countMatches(s.AnyOf, value) == 0 {
+		fail("must match at least one schema in anyOf")
+	}
+
+	if len(s.OneOf) > 0 && countMatches(s.OneOf, value) != 1 {
+		fail("must match exactly one schema in oneOf")
+	}
+
+	if s.Not != nil && countMatches([]*jsonSchema{s.Not}, value) == 1 {
+		fail("must not match the schema in not")
+	}
+}
+
+func (s *jsonSchema) validateString(value string, fail func(string)) {
+	length := utf8.RuneCountInString(value)
+
+	if s.MinLength != nil && length < *s.MinLength {
+		fail("must have at least " + strconv.Itoa(*s.MinLength) + " characters")
+	}
+	if s.MaxLength != nil && length > *s.MaxLength {
+		fail("must have at most " + strconv.Itoa(*s.MaxLength) + " characters")
+	}
+	if s.pattern != nil && !s.pattern.MatchString(value) {
+		fail("must match pattern " + s.Pattern)
+	}
+	if s.Format != "" && !matchesFormat(value, s.Format) {
+		fail("must be a valid " + s.Format)
+	}
+}
+
+func (s *jsonSchema) validateNumber(value float64, fail func(string)) {
+	if s.Minimum != nil && value < *s.Minimum {
+		fail("must be at least " + formatNumber(*s.Minimum))
+	}
+	if s.Maximum != nil && value > *s.Maximum {
+		fail("must be at most " + formatNumber(*s.Maximum))
+	}
+	if s.ExclusiveMinimum != nil && value <= *s.ExclusiveMinimum {
+		fail("must be greater than " + formatNumber(*s.ExclusiveMinimum))
+	}
+	if s.ExclusiveMaximum != nil && value >= *s.ExclusiveMaximum {
+		fail("must be less than " + formatNumber(*s.ExclusiveMaximum))
+	}
+	if s.MultipleOf != nil && *s.MultipleOf > 0 {
+		quotient := value / *s.MultipleOf
+		if math.Abs(quotient-math.Round(quotient)) > 1e-9 {
+			fail("must be a multiple of " + formatNumber(*s.MultipleOf))
+		}
+	}
+}
+
+func (s *jsonSchema) validateArray(value []any, path string, problems *ValidationErrors, fail func(string)) {
+	if s.MinItems != nil && len(value) < *s.MinItems {
+		fail("must have at least " + strconv.Itoa(*s.MinItems) + " items")
+	}
+	if s.MaxItems != nil && len(value) > *s.MaxItems {
+		fail("must have at most " + strconv.Itoa(*s.MaxItems) + " items")
+	}
+
+	if s.UniqueItems {
+	unique:
+		for i := range value {
+			for j := i + 1; j < len(value); j++ {
+				if reflect.DeepEqual(value[i], value[j]) {
+					fail("must not contain duplicate items")
+					break unique
+				}
+			}
+		}
+	}
+
+	if s.Items != nil {
+		for i, item := range value {
+			s.Items.validate(item, path+"["+strconv.Itoa(i)+"]", problems)
+		}
+	}
+}
+
+func (s *jsonSchema) validateObject(value map[string]any, path string, problems *ValidationErrors, fail func(string)) {
+	for _, name := range s.Required {
+		if _, ok := value[name]; !ok {
+			*problems = append(*problems, FieldError{Field: joinSchemaPath(path, name), Message: "is required"})
+		}
+	}
+
+	names := []string{}
+	for name := range value {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if property, ok := s.Properties[name]; ok {
+			property.validate(value[name], joinSchemaPath(path, name), problems)
+			continue
+		}
+		if s.AdditionalProperties != nil {
+			s.AdditionalProperties.validate(value[name], joinSchemaPath(path, name), problems)
+		}
+	}
+}
+
+// countMatches reports how many of schemas accept value, stopping early once
+// it is clear more than one does.
+func countMatches(schemas []*jsonSchema, value any) int {
+	matches := 0
+	for _, schema := range schemas {
+		problems := ValidationErrors{}
+		schema.validate(value, "", &problems)
+		if len(problems) == 0 {
+			matches++
+		}
+		if matches > 1 {
+			break
+		}
+	}
+	return matches
+}
+
+func matchesType(value any, types []string) bool {
+	for _, name := range types {
+		switch typed := value.(type) {
+		case nil:
+			if name == "null" {
+				return true
+			}
+		case bool:
+			if name == "boolean" {
+				return true
+			}
+		case string:
+			if name == "string" {
+				return true
+			}
+		case float64:
+			if name == "number" || (name == "integer" && typed == math.Trunc(typed)) {
+				return true
+			}
+		case []any:
+			if name == "array" {
+				return true
+			}
+		case map[string]any:
+			if name == "object" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// matchesFormat checks the formats payloads lean on most. Other formats are
+// annotations only and always pass.
+func matchesFormat(value string, format string) bool {
+	switch format {
+	case "email":
+		address, err := mail.ParseAddress(value)
+		return err == nil && address.Address == value
+	case "uri":
+		parsed, err := url.Parse(value)
+		return err == nil && parsed.Scheme != ""
+	case "date-time":
+		_, err := time.Parse(time.RFC3339, value)
+		return err == nil
+	case "date":
+		_, err := time.Parse(time.DateOnly, value)
+		return err == nil
+	case "uuid":
+		return uuidPattern.MatchString(value)
+	}
+	return true
+}
+
+func joinSchemaPath(path string, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+func formatNumber(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
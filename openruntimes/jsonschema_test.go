@@ -0,0 +1,116 @@
+package openruntimes
+
+import (
+	"crypto/sha256"
+	"strings"
+	"testing"
+)
+
+var orderSchema = []byte(`{
+	"type": "object",
+	"required": ["name", "items"],
+	"additionalProperties": false,
+	"properties": {
+		"name": {"type": "string", "minLength": 3, "pattern": "^[a-z]+$"},
+		"email": {"type": "string", "format": "email"},
+		"kind": {"enum": ["retail", "wholesale"]},
+		"items": {"type": "array", "minItems": 1, "uniqueItems": true, "items": {"$ref": "#/$defs/item"}},
+		"total": {"type": "number", "exclusiveMinimum": 0, "multipleOf": 0.01}
+	},
+	"$defs": {
+		"item": {
+			"type": "object",
+			"required": ["qty"],
+			"properties": {"qty": {"type": "integer", "minimum": 1}}
+		}
+	}
+}`)
+
+func TestValidateJSONSchema(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want map[string]string
+	}{
+		{
+			name: "valid",
+			body: `{"name":"widget","email":"a@example.com","kind":"retail","items":[{"qty":1}],"total":9.99}`,
+			want: map[string]string{},
+		},
+		{
+			name: "missing required",
+			body: `{}`,
+			want: map[string]string{"name": "is required", "items": "is required"},
+		},
+		{
+			name: "nested problems",
+			body: `{"name":"ab","email":"nope","kind":"other","items":[{"qty":0.5},{}],"total":0,"extra":true}`,
+			want: map[string]string{
+				"name":         "must have at least 3 characters",
+				"email":        "must be a valid email",
+				"kind":         "must be one of the allowed values",
+				"items[0].qty": "must be of type integer",
+				"items[1].qty": "is required",
+				"total":        "must be greater than 0",
+				"extra":        "is not allowed",
+			},
+		},
+		{
+			name: "wrong root type",
+			body: `[1,2]`,
+			want: map[string]string{"": "must be of type object"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := newBindRequest("application/json", test.body)
+			got := validationFields(t, req.ValidateJSONSchema(orderSchema))
+
+			for field, message := range test.want {
+				if got[field] != message {
+					t.Errorf("%s: got %q, want %q", field, got[field], message)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateJSONSchemaErrors(t *testing.T) {
+	req := newBindRequest("application/json", `{`)
+	if err := req.ValidateJSONSchema(orderSchema); err == nil || !strings.HasPrefix(err.Error(), "invalid JSON body") {
+		t.Errorf("malformed body error = %v", err)
+	}
+
+	req = newBindRequest("application/json", `{}`)
+	for _, schema := range []string{`{"type": 5}`, `{"$ref": "#/nowhere"}`, `{"pattern": "("}`} {
+		if err := req.ValidateJSONSchema([]byte(schema)); err == nil || !strings.HasPrefix(err.Error(), "invalid JSON schema") {
+			t.Errorf("schema %s error = %v", schema, err)
+		}
+	}
+}
+
+func TestValidateJSONSchemaCachesCompiledSchema(t *testing.T) {
+	schema := []byte(`{"type": "object", "required": ["cached"]}`)
+	req := newBindRequest("application/json", `{"cached": true}`)
+
+	if err := req.ValidateJSONSchema(schema); err != nil {
+		t.Fatalf("ValidateJSONSchema() error = %v", err)
+	}
+
+	schemaMutex.RLock()
+	first := compiledSchemas[sha256.Sum256(schema)]
+	schemaMutex.RUnlock()
+
+	if err := req.ValidateJSONSchema(schema); err != nil {
+		t.Fatalf("ValidateJSONSchema() error = %v", err)
+	}
+
+	schemaMutex.RLock()
+	second := compiledSchemas[sha256.Sum256(schema)]
+	schemaMutex.RUnlock()
+
+	if first == nil || first != second {
+		t.Error("compiled schema was not reused")
+	}
+}
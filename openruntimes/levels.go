@@ -0,0 +1,29 @@
+package openruntimes
+
+import (
+	"strings"
+)
+
+var loggerLevels = map[string]int{
+	LOGGER_TYPE_DEBUG: 0,
+	LOGGER_TYPE_INFO:  1,
+	LOGGER_TYPE_LOG:   1,
+	LOGGER_TYPE_WARN:  2,
+	LOGGER_TYPE_ERROR: 3,
+}
+
+// IsLevelEnabled reports whether xtype passes the Level filter. Types without
+// a level, such as audit, are never filtered; an empty Level lets everything through.
+func (l *Logger) IsLevelEnabled(xtype string) bool {
+	minimum, ok := loggerLevels[strings.ToLower(l.Level)]
+	if !ok {
+		return true
+	}
+
+	level, ok := loggerLevels[xtype]
+	if !ok {
+		return true
+	}
+
+	return level >= minimum
+}
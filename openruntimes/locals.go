@@ -0,0 +1,57 @@
+package openruntimes
+
+import (
+	"sync"
+)
+
+// contextLocals is shared by copies of a Context, so values set by
+// middleware are visible to the handler.
+type contextLocals struct {
+	mutex  sync.RWMutex
+	values map[string]any
+}
+
+// Set stores a value for the rest of the execution, typically from
+// middleware for the handler: the current user, parsed claims and so on.
+func (c *Context) Set(key string, value any) {
+	if c.locals == nil {
+		c.locals = &contextLocals{}
+	}
+
+	c.locals.mutex.Lock()
+	defer c.locals.mutex.Unlock()
+
+	if c.locals.values == nil {
+		c.locals.values = map[string]any{}
+	}
+	c.locals.values[key] = value
+}
+
+func (c *Context) Get(key string) (any, bool) {
+	if c.locals == nil {
+		return nil, false
+	}
+
+	c.locals.mutex.RLock()
+	defer c.locals.mutex.RUnlock()
+
+	value, ok := c.locals.values[key]
+	return value, ok
+}
+
+// GetAs is Get with a type assertion; it reports false when the key is
+// missing or holds a value of another type.
+func GetAs[T any](c *Context, key string) (T, bool) {
+	var zero T
+
+	value, ok := c.Get(key)
+	if !ok {
+		return zero, false
+	}
+
+	typed, ok := value.(T)
+	if !ok {
+		return zero, false
+	}
+	return typed, true
+}
@@ -0,0 +1,129 @@
+package openruntimes
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+type Metrics struct {
+	mutex     sync.Mutex
+	durations map[string][]time.Duration
+	counters  map[string]int64
+}
+
+func NewMetrics() *Metrics {
+	return &Metrics{
+		durations: map[string][]time.Duration{},
+		counters:  map[string]int64{},
+	}
+}
+
+type DurationSummary struct {
+	Count int
+	Total time.Duration
+	Min   time.Duration
+	Max   time.Duration
+	P50   time.Duration
+	P95   time.Duration
+	P99   time.Duration
+}
+
+func (m *Metrics) Observe(name string, duration time.Duration) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.durations[name] = append(m.durations[name], duration)
+}
+
+func (m *Metrics) Add(name string, delta int64) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.counters[name] += delta
+}
+
+func (m *Metrics) Counters() map[string]int64 {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	counters := map[string]int64{}
+	for name, value := range m.counters {
+		counters[name] = value
+	}
+	return counters
+}
+
+func (m *Metrics) Durations() map[string]DurationSummary {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	summaries := map[string]DurationSummary{}
+	for name, samples := range m.durations {
+		summaries[name] = summarizeDurations(samples)
+	}
+	return summaries
+}
+
+func summarizeDurations(samples []time.Duration) DurationSummary {
+	sorted := append([]time.Duration{}, samples...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	summary := DurationSummary{Count: len(sorted)}
+	if len(sorted) == 0 {
+		return summary
+	}
+
+	for _, sample := range sorted {
+		summary.Total += sample
+	}
+
+	percentile := func(p float64) time.Duration {
+		return sorted[int(p*float64(len(sorted)-1))]
+	}
+
+	summary.Min = sorted[0]
+	summary.Max = sorted[len(sorted)-1]
+	summary.P50 = percentile(0.50)
+	summary.P95 = percentile(0.95)
+	summary.P99 = percentile(0.99)
+
+	return summary
+}
+
+type Timer struct {
+	name    string
+	start   time.Time
+	context *Context
+}
+
+func (t Timer) Stop() time.Duration {
+	duration := time.Since(t.start)
+
+	t.context.Metrics().Observe(t.name, duration)
+	t.context.Debug("Timer " + t.name + ": " + duration.String())
+
+	return duration
+}
+
+func (c *Context) Metrics() *Metrics {
+	if c.metrics == nil {
+		c.metrics = NewMetrics()
+	}
+	return c.metrics
+}
+
+// Time starts a timer; call Stop on the result, typically with defer.
+func (c *Context) Time(name string) Timer {
+	c.Metrics()
+
+	return Timer{
+		name:    name,
+		start:   time.Now(),
+		context: c,
+	}
+}
+
+func (c *Context) Count(name string, delta int64) {
+	c.Metrics().Add(name, delta)
+}
@@ -0,0 +1,18 @@
+package openruntimes
+
+type Handler func(*Context) Response
+
+type Middleware func(Handler) Handler
+
+// Chain composes middleware into one, with the first argument outermost:
+// Chain(a, b)(handler) runs a, then b, then handler. It returns a Middleware
+// rather than a Handler so the endpoint is supplied once, at the end, and a
+// chain can itself be nested in another Chain or a Router group.
+func Chain(middlewares ...Middleware) Middleware {
+	return func(handler Handler) Handler {
+		for i := len(middlewares) - 1; i >= 0; i-- {
+			handler = middlewares[i](handler)
+		}
+		return handler
+	}
+}
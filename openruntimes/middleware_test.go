@@ -0,0 +1,46 @@
+package openruntimes
+
+import (
+	"strings"
+	"testing"
+)
+
+func recordingMiddleware(name string, calls *[]string) Middleware {
+	return func(next Handler) Handler {
+		return func(c *Context) Response {
+			*calls = append(*calls, name+":before")
+			response := next(c)
+			*calls = append(*calls, name+":after")
+			return response
+		}
+	}
+}
+
+func TestChainOrder(t *testing.T) {
+	calls := []string{}
+
+	inner := Chain(recordingMiddleware("b", &calls), recordingMiddleware("c", &calls))
+	handler := Chain(recordingMiddleware("a", &calls), inner)(func(c *Context) Response {
+		calls = append(calls, "handler")
+		return c.Res.Text("ok")
+	})
+
+	c := NewContext(Logger{})
+	handler(&c)
+
+	want := "a:before b:before c:before handler c:after b:after a:after"
+	if got := strings.Join(calls, " "); got != want {
+		t.Errorf("calls = %q, want %q", got, want)
+	}
+}
+
+func TestChainWithoutMiddleware(t *testing.T) {
+	handler := Chain()(func(c *Context) Response {
+		return c.Res.Text("ok")
+	})
+
+	c := NewContext(Logger{})
+	if response := handler(&c); string(response.Body) != "ok" {
+		t.Errorf("body = %q, want %q", response.Body, "ok")
+	}
+}
@@ -0,0 +1,58 @@
+package openruntimes
+
+import (
+	"os"
+	"strings"
+)
+
+const MOCK_EXAMPLE_DEFAULT = "default"
+
+func IsMockMode() bool {
+	status := os.Getenv("OPEN_RUNTIMES_MOCK")
+	return status == "enabled" || status == "true"
+}
+
+// Mockable serves the route's canned examples instead of running the handler
+// while OPEN_RUNTIMES_MOCK is enabled. Clients pick an example with
+// "prefer: example=<name>", otherwise the "default" example is used.
+func Mockable(metadata RouteMetadata) Middleware {
+	return func(next Handler) Handler {
+		return func(c *Context) Response {
+			if !IsMockMode() || len(metadata.Examples) == 0 {
+				return next(c)
+			}
+
+			name := preferredExample(c.Req.Header("prefer"))
+			example, ok := metadata.Examples[name]
+			if !ok {
+				example, ok = metadata.Examples[MOCK_EXAMPLE_DEFAULT]
+			}
+			if !ok {
+				return next(c)
+			}
+
+			headers := map[string]string{}
+			for key, value := range example.Headers {
+				headers[key] = value
+			}
+			headers["x-open-runtimes-mock"] = "true"
+
+			statusCode := example.StatusCode
+			if statusCode == 0 {
+				statusCode = 200
+			}
+
+			return c.Res.Binary(example.Body, c.Res.WithStatusCode(statusCode), c.Res.WithHeaders(headers))
+		}
+	}
+}
+
+func preferredExample(prefer string) string {
+	for _, preference := range strings.Split(prefer, ",") {
+		key, value, found := strings.Cut(strings.TrimSpace(preference), "=")
+		if found && strings.EqualFold(strings.TrimSpace(key), "example") {
+			return strings.Trim(strings.TrimSpace(value), "\"")
+		}
+	}
+	return MOCK_EXAMPLE_DEFAULT
+}
@@ -0,0 +1,126 @@
+package openruntimes
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"math"
+)
+
+type MsgpackCodec struct{}
+
+func (MsgpackCodec) Marshal(v any) ([]byte, error) {
+	return MarshalMsgpack(v)
+}
+
+func (MsgpackCodec) Unmarshal(data []byte, v any) error {
+	return errors.New("decoding msgpack is not supported")
+}
+
+func MarshalMsgpack(v any) ([]byte, error) {
+	tree, err := newTree(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var buffer bytes.Buffer
+	writeMsgpack(&buffer, tree)
+
+	return buffer.Bytes(), nil
+}
+
+func writeMsgpack(buffer *bytes.Buffer, node treeNode) {
+	switch node.kind {
+	case treeMap:
+		writeMsgpackHeader(buffer, len(node.keys), 0x80, 0xde, 0xdf)
+		for i, key := range node.keys {
+			writeMsgpackString(buffer, key)
+			writeMsgpack(buffer, node.values[i])
+		}
+		return
+	case treeList:
+		writeMsgpackHeader(buffer, len(node.values), 0x90, 0xdc, 0xdd)
+		for _, value := range node.values {
+			writeMsgpack(buffer, value)
+		}
+		return
+	}
+
+	switch value := node.value.(type) {
+	case nil:
+		buffer.WriteByte(0xc0)
+	case bool:
+		if value {
+			buffer.WriteByte(0xc3)
+		} else {
+			buffer.WriteByte(0xc2)
+		}
+	case string:
+		writeMsgpackString(buffer, value)
+	case json.Number:
+		if integer, err := value.Int64(); err == nil {
+			writeMsgpackInt(buffer, integer)
+			return
+		}
+
+		float, _ := value.Float64()
+		buffer.WriteByte(0xcb)
+		binary.Write(buffer, binary.BigEndian, math.Float64bits(float))
+	}
+}
+
+// writeMsgpackHeader picks the fix, 16-bit or 32-bit form of a map or array header.
+func writeMsgpackHeader(buffer *bytes.Buffer, length int, fix byte, header16 byte, header32 byte) {
+	switch {
+	case length < 16:
+		buffer.WriteByte(fix | byte(length))
+	case length <= math.MaxUint16:
+		buffer.WriteByte(header16)
+		binary.Write(buffer, binary.BigEndian, uint16(length))
+	default:
+		buffer.WriteByte(header32)
+		binary.Write(buffer, binary.BigEndian, uint32(length))
+	}
+}
+
+func writeMsgpackString(buffer *bytes.Buffer, value string) {
+	length := len(value)
+
+	switch {
+	case length < 32:
+		buffer.WriteByte(0xa0 | byte(length))
+	case length <= math.MaxUint8:
+		buffer.WriteByte(0xd9)
+		buffer.WriteByte(byte(length))
+	case length <= math.MaxUint16:
+		buffer.WriteByte(0xda)
+		binary.Write(buffer, binary.BigEndian, uint16(length))
+	default:
+		buffer.WriteByte(0xdb)
+		binary.Write(buffer, binary.BigEndian, uint32(length))
+	}
+
+	buffer.WriteString(value)
+}
+
+func writeMsgpackInt(buffer *bytes.Buffer, value int64) {
+	switch {
+	case value >= 0 && value <= 127:
+		buffer.WriteByte(byte(value))
+	case value < 0 && value >= -32:
+		buffer.WriteByte(byte(value))
+	case value >= math.MinInt8 && value <= math.MaxInt8:
+		buffer.WriteByte(0xd0)
+		buffer.WriteByte(byte(value))
+	case value >= math.MinInt16 && value <= math.MaxInt16:
+		buffer.WriteByte(0xd1)
+		binary.Write(buffer, binary.BigEndian, int16(value))
+	case value >= math.MinInt32 && value <= math.MaxInt32:
+		buffer.WriteByte(0xd2)
+		binary.Write(buffer, binary.BigEndian, int32(value))
+	default:
+		buffer.WriteByte(0xd3)
+		binary.Write(buffer, binary.BigEndian, value)
+	}
+}
@@ -0,0 +1,131 @@
+package openruntimes
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/open-runtimes/types-for-go/v4/mimetypes"
+)
+
+// Negotiate picks the best of available for an Accept header, preferring
+// higher quality, then more specific ranges, then the order of available.
+// An empty Accept header selects the first available type.
+func Negotiate(accept string, available []string) (string, bool) {
+	if len(available) == 0 {
+		return "", false
+	}
+
+	if strings.TrimSpace(accept) == "" {
+		return available[0], true
+	}
+
+	type acceptRange struct {
+		mediaType   string
+		quality     float64
+		specificity int
+	}
+
+	ranges := []acceptRange{}
+	for _, entry := range strings.Split(accept, ",") {
+		parts := strings.Split(entry, ";")
+		mediaType := mimetypes.Essence(parts[0])
+		if mediaType == "" {
+			continue
+		}
+
+		quality := 1.0
+		for _, param := range parts[1:] {
+			key, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+			if strings.TrimSpace(key) == "q" {
+				if parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil {
+					quality = parsed
+				}
+			}
+		}
+
+		specificity := 2
+		if mediaType == "*/*" {
+			specificity = 0
+		} else if strings.HasSuffix(mediaType, "/*") {
+			specificity = 1
+		}
+
+		ranges = append(ranges, acceptRange{mediaType, quality, specificity})
+	}
+
+	sort.SliceStable(ranges, func(i, j int) bool {
+		return ranges[i].specificity > ranges[j].specificity
+	})
+
+	best := ""
+	bestQuality := 0.0
+
+	for _, candidate := range available {
+		essence := mimetypes.Essence(candidate)
+		typePrefix, _, _ := strings.Cut(essence, "/")
+
+		for _, r := range ranges {
+			if r.mediaType == essence || r.mediaType == "*/*" || r.mediaType == typePrefix+"/*" {
+				if r.quality > bestQuality {
+					best = candidate
+					bestQuality = r.quality
+				}
+				break
+			}
+		}
+	}
+
+	return best, best != ""
+}
+
+// RespondMediaTypes lists, in server preference order, what Respond can emit.
+func RespondMediaTypes() []string {
+	mediaTypes := []string{"application/json"}
+
+	for _, mediaType := range RegisteredMediaTypes() {
+		if mediaType != "application/json" {
+			mediaTypes = append(mediaTypes, mediaType)
+		}
+	}
+
+	return mediaTypes
+}
+
+// Respond encodes v in the best type the Accept header allows. When a codec
+// cannot encode v, as XML cannot encode maps, the next acceptable type is
+// tried; if none is left the response is 406.
+func (c *Context) Respond(v any, optionalSetters ...ResponseOption) Response {
+	accept := c.Req.Header("accept")
+	available := RespondMediaTypes()
+
+	optionalSetters = append(optionalSetters, withVary("Accept"))
+
+	for {
+		mediaType, ok := Negotiate(accept, available)
+		if !ok {
+			break
+		}
+
+		if mediaType == "application/json" {
+			return c.Res.Json(v, optionalSetters...)
+		}
+
+		if codec, ok := lookupEncodingCodec(mediaType); ok {
+			if data, err := codec.Marshal(v); err == nil {
+				optionalSetters = append(optionalSetters, c.Res.WithHeaders(map[string]string{"content-type": mediaType}))
+				return c.Res.Binary(data, optionalSetters...)
+			}
+		}
+
+		remaining := []string{}
+		for _, candidate := range available {
+			if candidate != mediaType {
+				remaining = append(remaining, candidate)
+			}
+		}
+		available = remaining
+	}
+
+	return c.Res.Json(errorBody("Not Acceptable. Supported types: "+strings.Join(RespondMediaTypes(), ", ")), c.Res.WithStatusCode(406), withVary("Accept"))
+}
@@ -0,0 +1,55 @@
+package openruntimes
+
+import (
+	"strings"
+	"testing"
+)
+
+type negotiationItem struct {
+	Name string `json:"name" xml:"name"`
+}
+
+func TestRespond(t *testing.T) {
+	tests := []struct {
+		name        string
+		accept      string
+		value       any
+		status      int
+		contentType string
+	}{
+		{"json by default", "", map[string]any{"name": "a"}, 200, "application/json"},
+		{"xml for a struct", "application/xml", negotiationItem{Name: "a"}, 200, "application/xml"},
+		{"xml falls back for a map", "application/xml, application/json;q=0.5", map[string]any{"name": "a"}, 200, "application/json"},
+		{"not acceptable when nothing can encode", "application/xml", map[string]any{"name": "a"}, 406, "application/json"},
+		{"encode-only yaml", "application/yaml", map[string]any{"name": "a"}, 200, "application/yaml"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := NewContext(Logger{})
+			c.Req.Headers = map[string]string{"accept": test.accept}
+
+			response := c.Respond(test.value)
+
+			if response.StatusCode != test.status {
+				t.Errorf("status = %d, want %d (body %q)", response.StatusCode, test.status, response.Body)
+			}
+			if contentType := response.Headers["content-type"]; !strings.HasPrefix(contentType, test.contentType) {
+				t.Errorf("content-type = %q, want %q", contentType, test.contentType)
+			}
+			if vary := response.Headers["vary"]; vary != "Accept" {
+				t.Errorf("vary = %q, want %q", vary, "Accept")
+			}
+		})
+	}
+}
+
+func TestRespondKeepsVary(t *testing.T) {
+	c := NewContext(Logger{})
+
+	response := c.Respond(map[string]any{}, c.Res.WithHeaders(map[string]string{"vary": "Origin"}))
+
+	if vary := response.Headers["vary"]; vary != "Origin, Accept" {
+		t.Fatalf("vary = %q, want %q", vary, "Origin, Accept")
+	}
+}
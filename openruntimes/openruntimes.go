@@ -1,32 +1,79 @@
 package openruntimes
 
 import (
+	"bufio"
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"log"
 	"math/rand"
+	"mime"
 	"os"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
+
+	"github.com/open-runtimes/types-for-go/v4/mimetypes"
 )
 
 const LOGGER_TYPE_LOG = "log"
 const LOGGER_TYPE_ERROR = "error"
+const LOGGER_TYPE_AUDIT = "audit"
+const LOGGER_TYPE_DEBUG = "debug"
+const LOGGER_TYPE_INFO = "info"
+const LOGGER_TYPE_WARN = "warn"
 
 type Context struct {
-	logger Logger
+	ctx          context.Context
+	logger       Logger
+	metrics      *Metrics
+	locals       *contextLocals
+	execution    *Execution
+	container    *executionContainer
+	coldStart    bool
+	initDuration time.Duration
 
 	Req ContextRequest
 	Res ContextResponse
 }
 
 func NewContext(logger Logger) Context {
-	return Context{
-		logger: logger,
+	coldStart, initDuration := markInvocation()
+
+	context := Context{
+		logger:       logger,
+		metrics:      NewMetrics(),
+		locals:       &contextLocals{},
+		container:    &executionContainer{},
+		coldStart:    coldStart,
+		initDuration: initDuration,
+	}
+
+	if coldStart {
+		context.metrics.Add(METRIC_COLD_START, 1)
+		context.metrics.Observe(METRIC_INIT_DURATION, initDuration)
+	} else {
+		context.metrics.Add(METRIC_COLD_START, 0)
+	}
+
+	return context
+}
+
+// Context returns the standard context of the execution, to pass on to
+// database drivers, HTTP clients and SDKs. It is never nil.
+func (c *Context) Context() context.Context {
+	if c.ctx == nil {
+		return context.Background()
 	}
+	return c.ctx
+}
+
+func (c *Context) SetContext(ctx context.Context) {
+	c.ctx = ctx
 }
 
 type Log struct {
@@ -37,33 +84,188 @@ func (l Log) String() string {
 	return l.Message
 }
 
+// Log and the other Context logging methods are safe for concurrent use; each
+// call is written as one uninterrupted line.
 func (c *Context) Log(messages ...interface{}) {
-	c.logger.Write(messages, LOGGER_TYPE_LOG, false)
-	c.logger.Write([]interface{}{"\n"}, LOGGER_TYPE_LOG, false)
+	c.logger.writeLine(messages, LOGGER_TYPE_LOG)
 }
 
 func (c *Context) Error(messages ...interface{}) {
-	c.logger.Write(messages, LOGGER_TYPE_ERROR, false)
-	c.logger.Write([]interface{}{"\n"}, LOGGER_TYPE_ERROR, false)
+	c.logger.writeLine(messages, LOGGER_TYPE_ERROR)
+}
+
+func (c *Context) Debug(messages ...interface{}) {
+	c.logger.writeLine(messages, LOGGER_TYPE_DEBUG)
+}
+
+func (c *Context) Info(messages ...interface{}) {
+	c.logger.writeLine(messages, LOGGER_TYPE_INFO)
+}
+
+func (c *Context) Warn(messages ...interface{}) {
+	c.logger.writeLine(messages, LOGGER_TYPE_WARN)
+}
+
+func (c *Context) Logf(format string, args ...any) {
+	c.logger.writeLine([]interface{}{fmt.Sprintf(format, args...)}, LOGGER_TYPE_LOG)
+}
+
+func (c *Context) Errorf(format string, args ...any) {
+	c.logger.writeLine([]interface{}{fmt.Sprintf(format, args...)}, LOGGER_TYPE_ERROR)
+}
+
+func (c *Context) Debugf(format string, args ...any) {
+	c.logger.writeLine([]interface{}{fmt.Sprintf(format, args...)}, LOGGER_TYPE_DEBUG)
+}
+
+func (c *Context) Infof(format string, args ...any) {
+	c.logger.writeLine([]interface{}{fmt.Sprintf(format, args...)}, LOGGER_TYPE_INFO)
+}
+
+func (c *Context) Warnf(format string, args ...any) {
+	c.logger.writeLine([]interface{}{fmt.Sprintf(format, args...)}, LOGGER_TYPE_WARN)
+}
+
+func (c *Context) Audit(action string, actor string, target string, outcome string) {
+	c.logger.Audit(action, actor, target, outcome)
 }
 
 type ContextRequest struct {
-	bodyBinary  []byte
-	Headers     map[string]string
-	Method      string
-	Url         string
-	Path        string
-	Port        int
-	Scheme      string
-	Host        string
-	QueryString string
-	Query       map[string]string
+	bodyBinary   []byte
+	headerValues map[string][]string
+	queryValues  map[string][]string
+	params       map[string]string
+	Headers      map[string]string
+	Method       string
+	Url          string
+	Path         string
+	Port         int
+	Scheme       string
+	Host         string
+	QueryString  string
+	Query        map[string]string
+}
+
+func (r ContextRequest) Clone() ContextRequest {
+	clone := r
+
+	if r.bodyBinary != nil {
+		clone.bodyBinary = append([]byte{}, r.bodyBinary...)
+	}
+
+	clone.Headers = cloneStringMap(r.Headers)
+	clone.Query = cloneStringMap(r.Query)
+	clone.headerValues = cloneMultiMap(r.headerValues)
+	clone.queryValues = cloneMultiMap(r.queryValues)
+	clone.params = cloneStringMap(r.params)
+
+	return clone
+}
+
+func cloneStringMap(source map[string]string) map[string]string {
+	if source == nil {
+		return nil
+	}
+
+	clone := make(map[string]string, len(source))
+	for key, value := range source {
+		clone[key] = value
+	}
+	return clone
+}
+
+func cloneMultiMap(source map[string][]string) map[string][]string {
+	if source == nil {
+		return nil
+	}
+
+	clone := make(map[string][]string, len(source))
+	for key, values := range source {
+		clone[key] = append([]string{}, values...)
+	}
+	return clone
 }
 
 func (r *ContextRequest) SetBodyBinary(bytes []byte) {
 	r.bodyBinary = bytes
 }
 
+func (r *ContextRequest) SetHeaderValues(headers map[string][]string) {
+	r.headerValues = map[string][]string{}
+
+	if r.Headers == nil {
+		r.Headers = map[string]string{}
+	}
+
+	for key, values := range headers {
+		key = NormalizeHeaderKey(key)
+		r.headerValues[key] = append(r.headerValues[key], values...)
+	}
+
+	for key, values := range r.headerValues {
+		if _, ok := headerGet(r.Headers, key); !ok {
+			r.Headers[key] = strings.Join(values, ", ")
+		}
+	}
+}
+
+func (r ContextRequest) HeaderValues(key string) []string {
+	if values, ok := r.headerValues[key]; ok {
+		return values
+	}
+
+	for name, values := range r.headerValues {
+		if strings.EqualFold(name, key) {
+			return values
+		}
+	}
+
+	if value, ok := r.lookupHeader(key); ok {
+		return []string{value}
+	}
+
+	return []string{}
+}
+
+func (r ContextRequest) Header(key string) string {
+	value, _ := r.lookupHeader(key)
+	return value
+}
+
+func (r *ContextRequest) SetHeader(key string, value string) {
+	if r.Headers == nil {
+		r.Headers = map[string]string{}
+	}
+
+	headerSet(r.Headers, key, value)
+}
+
+// stripHeaders removes every header whose name starts with prefix, from both
+// Headers and the values HeaderValues reads.
+func (r *ContextRequest) stripHeaders(prefix string) {
+	prefix = strings.ToLower(prefix)
+
+	for key := range r.Headers {
+		if strings.HasPrefix(strings.ToLower(key), prefix) {
+			delete(r.Headers, key)
+		}
+	}
+
+	for key := range r.headerValues {
+		if strings.HasPrefix(strings.ToLower(key), prefix) {
+			delete(r.headerValues, key)
+		}
+	}
+}
+
+func (r ContextRequest) lookupHeader(key string) (string, bool) {
+	if value, ok := r.Headers[key]; ok {
+		return value, true
+	}
+
+	return headerGet(r.Headers, key)
+}
+
 func (r ContextRequest) BodyBinary() []byte {
 	return r.bodyBinary
 }
@@ -88,10 +290,27 @@ func (r ContextRequest) BodyJson(v any) error {
 	return nil
 }
 
+func (r ContextRequest) ContentType() (string, map[string]string) {
+	header := r.Header("content-type")
+	if header == "" {
+		return "", map[string]string{}
+	}
+
+	mediaType, params, err := mime.ParseMediaType(header)
+	if err != nil {
+		return mimetypes.Essence(header), map[string]string{}
+	}
+
+	return mediaType, params
+}
+
+// Body decodes JSON, and bodies a registered codec understands, into a map;
+// anything else, binary content included, is returned as a string. Use
+// BodyBinary for the raw bytes.
 func (r ContextRequest) Body() interface{} {
-	contentType := r.Headers["content-type"]
+	contentType, _ := r.ContentType()
 
-	if contentType == "application/json" {
+	if mimetypes.IsJson(contentType) {
 		if len(r.bodyBinary) == 0 {
 			return map[string]interface{}{}
 		}
@@ -106,34 +325,69 @@ func (r ContextRequest) Body() interface{} {
 		return bodyJson
 	}
 
+	if codec, ok := LookupCodec(contentType); ok && len(r.bodyBinary) > 0 {
+		var bodyDecoded map[string]interface{}
+		if err := codec.Unmarshal(r.bodyBinary, &bodyDecoded); err == nil {
+			return bodyDecoded
+		}
+	}
+
 	return r.BodyText()
 }
 
 type Response struct {
-	Body       []byte
-	StatusCode int
-	Headers    map[string]string
+	Body         []byte
+	BodyReader   io.Reader
+	StatusCode   int
+	Headers      map[string]string
+	HeaderValues map[string][]string
+	Cookies      []Cookie
+	Trailers     map[string]string
+
+	jsonIndent     string
+	jsonEscapeHTML bool
+	compression    string
 
 	enabledSetters map[string]bool
 }
 
 func (r Response) New() *Response {
 	r.enabledSetters = map[string]bool{
-		"Body":       false,
-		"StatusCode": false,
-		"Headers":    false,
+		"Body":           false,
+		"StatusCode":     false,
+		"Headers":        false,
+		"JsonIndent":     false,
+		"JsonEscapeHTML": false,
+		"ETag":           false,
+		"CsvBOM":         false,
 	}
 	return &r
 }
 
+func (r *Response) mergeHeaders(headers map[string]string) {
+	merged := map[string]string{}
+
+	if r.enabledSetters["Headers"] {
+		for key, value := range r.Headers {
+			merged[key] = value
+		}
+	}
+
+	for key, value := range headers {
+		headerSet(merged, key, value)
+	}
+
+	r.Headers = merged
+	r.enabledSetters["Headers"] = true
+}
+
 type ResponseOption func(*Response)
 
 type ContextResponse struct{}
 
 func (r ContextResponse) WithHeaders(headers map[string]string) ResponseOption {
 	return func(o *Response) {
-		o.Headers = headers
-		o.enabledSetters["Headers"] = true
+		o.mergeHeaders(headers)
 	}
 }
 
@@ -144,7 +398,27 @@ func (r ContextResponse) WithStatusCode(statusCode int) ResponseOption {
 	}
 }
 
+func (r ContextResponse) WithIndent(indent string) ResponseOption {
+	return func(o *Response) {
+		o.jsonIndent = indent
+		o.enabledSetters["JsonIndent"] = true
+	}
+}
+
+func (r ContextResponse) WithEscapeHTML(escapeHTML bool) ResponseOption {
+	return func(o *Response) {
+		o.jsonEscapeHTML = escapeHTML
+		o.enabledSetters["JsonEscapeHTML"] = true
+	}
+}
+
 func (r ContextResponse) Binary(bytes []byte, optionalSetters ...ResponseOption) Response {
+	return r.binary(bytes, false, optionalSetters...)
+}
+
+// binary builds a response around bytes; streamed skips what depends on the
+// whole body, which a stream does not have yet.
+func (r ContextResponse) binary(bytes []byte, streamed bool, optionalSetters ...ResponseOption) Response {
 	options := Response{}.New()
 	for _, opt := range optionalSetters {
 		opt(options)
@@ -161,10 +435,28 @@ func (r ContextResponse) Binary(bytes []byte, optionalSetters ...ResponseOption)
 		statusCode = options.StatusCode
 	}
 
+	if options.compression != "" && len(bytes) > 0 {
+		compressed, err := compressBody(options.compression, bytes)
+		if err == nil {
+			bytes = compressed
+			headers = compressedHeaders(headers, options.compression, len(compressed))
+		}
+	}
+
+	if options.enabledSetters["ETag"] && !streamed {
+		headers = cloneStringMap(headers)
+		headerSet(headers, "etag", ComputeETag(bytes))
+	}
+
+	headers = normalizeHeaderMap(headers)
+
 	return Response{
-		Body:       bytes,
-		StatusCode: statusCode,
-		Headers:    headers,
+		Body:         bytes,
+		StatusCode:   statusCode,
+		Headers:      headers,
+		HeaderValues: options.HeaderValues,
+		Cookies:      options.Cookies,
+		Trailers:     options.Trailers,
 	}
 }
 
@@ -187,18 +479,38 @@ func (r ContextResponse) Json(bodyStruct interface{}, optionalSetters ...Respons
 		headers = options.Headers
 	}
 
-	headers["content-type"] = "application/json"
+	headerSet(headers, "content-type", "application/json")
 	optionalSetters = append(optionalSetters, r.WithHeaders(headers))
 
-	jsonData, err := json.Marshal(bodyStruct)
+	var body bytes.Buffer
+	encoder := json.NewEncoder(&body)
+
+	if options.enabledSetters["JsonIndent"] {
+		encoder.SetIndent("", options.jsonIndent)
+	}
+
+	if options.enabledSetters["JsonEscapeHTML"] {
+		encoder.SetEscapeHTML(options.jsonEscapeHTML)
+	}
+
+	err := encoder.Encode(bodyStruct)
 	if err != nil {
 		optionalSetters = append(optionalSetters, r.WithStatusCode(500))
 		return r.Text("Error encoding JSON.", optionalSetters...)
 	}
 
-	jsonString := string(jsonData[:])
+	// Encoder terminates every value with a newline that json.Marshal does not emit.
+	body.Truncate(body.Len() - 1)
 
-	return r.Text(jsonString, optionalSetters...)
+	return r.Binary(body.Bytes(), optionalSetters...)
+}
+
+func (r ContextResponse) Html(body string, optionalSetters ...ResponseOption) Response {
+	optionalSetters = append(optionalSetters, r.WithHeaders(map[string]string{
+		"content-type": "text/html; charset=utf-8",
+	}))
+
+	return r.Text(body, optionalSetters...)
 }
 
 func (r ContextResponse) Empty() Response {
@@ -206,20 +518,38 @@ func (r ContextResponse) Empty() Response {
 }
 
 func (r ContextResponse) Redirect(url string, optionalSetters ...ResponseOption) Response {
+	return r.redirect(301, url, optionalSetters)
+}
+
+func (r ContextResponse) RedirectPermanent(url string, optionalSetters ...ResponseOption) Response {
+	return r.redirect(308, url, optionalSetters)
+}
+
+func (r ContextResponse) RedirectTemporary(url string, optionalSetters ...ResponseOption) Response {
+	return r.redirect(307, url, optionalSetters)
+}
+
+func (r ContextResponse) RedirectSeeOther(url string, optionalSetters ...ResponseOption) Response {
+	return r.redirect(303, url, optionalSetters)
+}
+
+// redirect uses defaultStatusCode unless the caller passed WithStatusCode,
+// which always wins regardless of its position among the options.
+func (r ContextResponse) redirect(defaultStatusCode int, url string, optionalSetters []ResponseOption) Response {
 	options := Response{}.New()
 	for _, opt := range optionalSetters {
 		opt(options)
 	}
 
-	headers := map[string]string{}
-	if options.enabledSetters["Headers"] {
-		headers = options.Headers
+	statusCode := defaultStatusCode
+	if options.enabledSetters["StatusCode"] {
+		statusCode = options.StatusCode
 	}
 
-	headers["location"] = url
-	optionalSetters = append(optionalSetters, r.WithHeaders(headers))
-
-	optionalSetters = append([]ResponseOption{r.WithStatusCode(301)}, optionalSetters...)
+	optionalSetters = append(optionalSetters,
+		r.WithHeaders(map[string]string{"location": url}),
+		r.WithStatusCode(statusCode),
+	)
 
 	return r.Text("", optionalSetters...)
 }
@@ -228,9 +558,14 @@ type Logger struct {
 	Enabled            bool
 	Id                 string
 	IncludesNativeInfo bool
+	Level              string
+	Format             string
+	Prefix             bool
+	Console            bool
 
 	StreamLogs   *os.File
 	StreamErrors *os.File
+	StreamAudit  *os.File
 
 	NativeStreamLogs   chan string
 	NativeStreamErrors chan string
@@ -240,11 +575,43 @@ type Logger struct {
 
 	NativeLogsCache   *os.File
 	NativeErrorsCache *os.File
+
+	writerLogs   io.Writer
+	writerErrors io.Writer
+	writerAudit  io.Writer
+
+	lastAuditHash string
+
+	midLineLogs    bool
+	midLineErrors  bool
+	midLineConsole bool
+
+	maxMessageBytes int
+
+	traceId   string
+	requestId string
+
+	fields   map[string]any
+	closers  []io.Closer
+	sampler  *logSampler
+	counters *logCounters
+	exporter *otlpExporter
+	mutex    *sync.Mutex
 }
 
 func NewLogger(status string, id string) (Logger, error) {
+	return NewLoggerWithOptions(status, id, LoggerOptions{})
+}
+
+func NewLoggerWithOptions(status string, id string, options LoggerOptions) (Logger, error) {
 	logger := Logger{
 		IncludesNativeInfo: false,
+		Level:              os.Getenv("OPEN_RUNTIMES_LOG_LEVEL"),
+		Format:             os.Getenv("OPEN_RUNTIMES_LOG_FORMAT"),
+		Prefix:             os.Getenv("OPEN_RUNTIMES_LOG_PREFIX") == "enabled",
+		mutex:              &sync.Mutex{},
+		counters:           &logCounters{},
+		maxMessageBytes:    options.MaxMessageBytes,
 	}
 
 	if status == "" || status == "enabled" {
@@ -256,6 +623,7 @@ func NewLogger(status string, id string) (Logger, error) {
 
 	if logger.Enabled {
 		serverEnv := os.Getenv("OPEN_RUNTIMES_ENV")
+		logger.Console = serverEnv == "development"
 
 		if id == "" {
 			if serverEnv == "development" {
@@ -267,32 +635,44 @@ func NewLogger(status string, id string) (Logger, error) {
 			logger.Id = id
 		}
 
-		fileLogs, err := os.OpenFile("/mnt/logs/"+logger.Id+"_logs.log", os.O_APPEND|os.O_WRONLY|os.O_CREATE, 0644)
-		if err != nil {
-			return Logger{}, errors.New("could not prepare log file")
+		if err := logger.prepareWriters(options); err != nil {
+			return Logger{}, err
 		}
-		logger.StreamLogs = fileLogs
 
-		fileErrors, err := os.OpenFile("/mnt/logs/"+logger.Id+"_errors.log", os.O_APPEND|os.O_WRONLY|os.O_CREATE, 0644)
-		if err != nil {
-			return Logger{}, errors.New("could not prepare log file")
-		}
-		logger.StreamErrors = fileErrors
+		logger.sampler = newLogSampler(options)
+		logger.exporter = newOtlpExporter(logger.Id)
 	}
 
 	return logger, nil
 }
 
+// Write is safe for concurrent use. Copies of a Logger share its lock, as
+// they share its streams.
 func (l *Logger) Write(messages []interface{}, xtype string, xnative bool) {
-	if xnative && !l.IncludesNativeInfo {
-		l.IncludesNativeInfo = true
-		l.Write([]interface{}{"Native logs detected. Use context.Log() or context.Error() for better experience."}, xtype, xnative)
+	defer l.lock()()
+
+	l.write(messages, xtype, xnative)
+}
+
+func (l *Logger) writeLine(messages []interface{}, xtype string) {
+	defer l.lock()()
+
+	if !l.IsLevelEnabled(xtype) || !l.sample(xtype) {
+		return
 	}
 
-	stream := l.StreamLogs
+	l.write(messages, xtype, false)
+	l.write([]interface{}{"\n"}, xtype, false)
+}
+
+func (l *Logger) write(messages []interface{}, xtype string, xnative bool) {
+	if !xnative && !l.IsLevelEnabled(xtype) {
+		return
+	}
 
-	if xtype == LOGGER_TYPE_ERROR {
-		stream = l.StreamErrors
+	if xnative && !l.IncludesNativeInfo {
+		l.IncludesNativeInfo = true
+		l.write([]interface{}{"Native logs detected. Use context.Log() or context.Error() for better experience."}, xtype, xnative)
 	}
 
 	stringLog := ""
@@ -316,18 +696,35 @@ func (l *Logger) Write(messages []interface{}, xtype string, xnative bool) {
 		i++
 	}
 
-	stream.Write([]byte(stringLog))
+	l.writeRecord(xtype, stringLog, nil, "")
 }
 
 func (l *Logger) End() {
+	defer l.lock()()
+
 	if !l.Enabled {
 		return
 	}
 
 	l.Enabled = false
 
-	l.StreamLogs.Close()
-	l.StreamErrors.Close()
+	l.writeSuppressed()
+	l.Flush()
+
+	if l.exporter != nil {
+		l.exporter.export()
+	}
+
+	for _, closer := range l.closers {
+		closer.Close()
+	}
+
+	for _, stream := range []*os.File{l.StreamLogs, l.StreamErrors, l.StreamAudit} {
+		if stream != nil {
+			stream.Sync()
+			stream.Close()
+		}
+	}
 }
 
 func (l *Logger) OverrideNativeLogs() error {
@@ -352,22 +749,37 @@ func (l *Logger) OverrideNativeLogs() error {
 	log.SetOutput(writerErrors)
 
 	l.NativeStreamLogs = make(chan string)
-	go func() {
-		var buf bytes.Buffer
-		io.Copy(&buf, readerLogs)
-		l.NativeStreamLogs <- buf.String()
-	}()
+	go l.captureNative(readerLogs, LOGGER_TYPE_LOG, l.NativeStreamLogs)
 
 	l.NativeStreamErrors = make(chan string)
-	go func() {
-		var buf bytes.Buffer
-		io.Copy(&buf, readerErrors)
-		l.NativeStreamErrors <- buf.String()
-	}()
+	go l.captureNative(readerErrors, LOGGER_TYPE_ERROR, l.NativeStreamErrors)
+
+	l.watchCrashes()
 
 	return nil
 }
 
+// captureNative writes native output to the log as each line arrives, so
+// nothing is held in memory and a crash keeps what was printed before it.
+// Lines longer than the read buffer are written in pieces. The done channel
+// is closed once the pipe is drained.
+func (l *Logger) captureNative(reader io.ReadCloser, xtype string, done chan string) {
+	defer close(done)
+	defer reader.Close()
+
+	buffered := bufio.NewReaderSize(reader, LOGGER_MESSAGE_MAX_BYTES)
+	for {
+		line, err := buffered.ReadSlice('\n')
+		if len(line) > 0 {
+			l.Write([]interface{}{string(line)}, xtype, true)
+		}
+
+		if err != nil && err != bufio.ErrBufferFull {
+			return
+		}
+	}
+}
+
 func (l *Logger) RevertNativeLogs() {
 	l.WriterLogs.Close()
 	l.WriterErrors.Close()
@@ -376,15 +788,10 @@ func (l *Logger) RevertNativeLogs() {
 	os.Stderr = l.NativeErrorsCache
 	log.SetOutput(os.Stderr)
 
-	customLogs := <-l.NativeStreamLogs
-	if customLogs != "" {
-		l.Write([]interface{}{customLogs}, LOGGER_TYPE_LOG, true)
-	}
+	<-l.NativeStreamLogs
+	<-l.NativeStreamErrors
 
-	customErrors := <-l.NativeStreamErrors
-	if customErrors != "" {
-		l.Write([]interface{}{customLogs}, LOGGER_TYPE_ERROR, true)
-	}
+	l.unwatchCrashes()
 }
 
 func (l Logger) generateId(padding int) string {
@@ -0,0 +1,126 @@
+package openruntimes
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newFileLogger(t *testing.T, options LoggerOptions) (Logger, string) {
+	t.Helper()
+
+	options.Dir = t.TempDir()
+
+	logger, err := NewLoggerWithOptions("enabled", "test", options)
+	if err != nil {
+		t.Fatalf("NewLoggerWithOptions() error = %v", err)
+	}
+
+	return logger, options.Dir
+}
+
+func readLog(t *testing.T, dir string, suffix string) string {
+	t.Helper()
+
+	data, err := os.ReadFile(filepath.Join(dir, "test"+suffix))
+	if err != nil {
+		t.Fatalf("reading %s: %v", suffix, err)
+	}
+	return string(data)
+}
+
+func TestNativeLogsGoToTheirOwnStreams(t *testing.T) {
+	logger, dir := newFileLogger(t, LoggerOptions{})
+
+	if err := logger.OverrideNativeLogs(); err != nil {
+		t.Fatalf("OverrideNativeLogs() error = %v", err)
+	}
+	fmt.Println("native stdout line")
+	fmt.Fprintln(os.Stderr, "native stderr line")
+	logger.RevertNativeLogs()
+	logger.End()
+
+	logs := readLog(t, dir, "_logs.log")
+	errors := readLog(t, dir, "_errors.log")
+
+	if !strings.Contains(logs, "native stdout line") || strings.Contains(logs, "native stderr line") {
+		t.Errorf("logs = %q, want only the stdout line", logs)
+	}
+	if !strings.Contains(errors, "native stderr line") || strings.Contains(errors, "native stdout line") {
+		t.Errorf("errors = %q, want only the stderr line", errors)
+	}
+}
+
+func TestNativeLogsRestoreStreams(t *testing.T) {
+	stdout, stderr := os.Stdout, os.Stderr
+	logger, _ := newFileLogger(t, LoggerOptions{})
+
+	if err := logger.OverrideNativeLogs(); err != nil {
+		t.Fatalf("OverrideNativeLogs() error = %v", err)
+	}
+	if os.Stdout == stdout || os.Stderr == stderr {
+		t.Fatal("OverrideNativeLogs() left the native streams in place")
+	}
+
+	logger.RevertNativeLogs()
+	logger.End()
+
+	if os.Stdout != stdout || os.Stderr != stderr {
+		t.Fatal("RevertNativeLogs() did not restore the native streams")
+	}
+}
+
+func TestNativeErrorsWrittenBeforeRevert(t *testing.T) {
+	logger, dir := newFileLogger(t, LoggerOptions{})
+
+	if err := logger.OverrideNativeLogs(); err != nil {
+		t.Fatalf("OverrideNativeLogs() error = %v", err)
+	}
+	defer func() {
+		logger.RevertNativeLogs()
+		logger.End()
+	}()
+
+	fmt.Fprintln(os.Stderr, "written before a crash")
+
+	deadline := time.Now().Add(2 * time.Second)
+	for !strings.Contains(readLog(t, dir, "_errors.log"), "written before a crash") {
+		if time.Now().After(deadline) {
+			t.Fatal("native stderr line did not reach the errors log before RevertNativeLogs")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestNativeLogsKeepLongLines(t *testing.T) {
+	logger, dir := newFileLogger(t, LoggerOptions{MaxMessageBytes: 1 << 20})
+
+	line := strings.Repeat("x", 3*LOGGER_MESSAGE_MAX_BYTES)
+
+	if err := logger.OverrideNativeLogs(); err != nil {
+		t.Fatalf("OverrideNativeLogs() error = %v", err)
+	}
+	fmt.Println(line)
+	logger.RevertNativeLogs()
+	logger.End()
+
+	if logs := readLog(t, dir, "_logs.log"); !strings.Contains(logs, line+"\n") {
+		t.Errorf("logs = %d bytes, want the %d byte line intact", len(logs), len(line))
+	}
+}
+
+func TestBodyReturnsTextForBinaryTypes(t *testing.T) {
+	for _, contentType := range []string{"application/octet-stream", "image/png", "text/plain", ""} {
+		t.Run(contentType, func(t *testing.T) {
+			req := ContextRequest{Headers: map[string]string{"content-type": contentType}}
+			req.SetBodyBinary([]byte("\x89PNG"))
+
+			if body, ok := req.Body().(string); !ok || body != "\x89PNG" {
+				t.Errorf("Body() = %#v, want the body as a string", req.Body())
+			}
+		})
+	}
+}
@@ -0,0 +1,299 @@
+package openruntimes
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const OTLP_TIMEOUT_DEFAULT = 10 * time.Second
+
+// OTLP_MAX_RECORDS caps what one execution holds in memory for export; later
+// records are counted and reported as dropped.
+const OTLP_MAX_RECORDS = 1000
+
+// OTLP_MAX_IN_FLIGHT caps concurrent exports, so an unreachable collector
+// costs dropped batches instead of a growing pile of waiting requests.
+const OTLP_MAX_IN_FLIGHT = 4
+
+var (
+	otlpInFlight = make(chan struct{}, OTLP_MAX_IN_FLIGHT)
+	otlpPending  sync.WaitGroup
+)
+
+var otlpSeverities = map[string]int{
+	LOGGER_TYPE_DEBUG: 5,
+	LOGGER_TYPE_INFO:  9,
+	LOGGER_TYPE_LOG:   9,
+	LOGGER_TYPE_WARN:  13,
+	LOGGER_TYPE_ERROR: 17,
+}
+
+type otlpValue struct {
+	StringValue *string  `json:"stringValue,omitempty"`
+	BoolValue   *bool    `json:"boolValue,omitempty"`
+	IntValue    *string  `json:"intValue,omitempty"`
+	DoubleValue *float64 `json:"doubleValue,omitempty"`
+}
+
+type otlpAttribute struct {
+	Key   string    `json:"key"`
+	Value otlpValue `json:"value"`
+}
+
+type otlpRecord struct {
+	TimeUnixNano   string          `json:"timeUnixNano"`
+	SeverityNumber int             `json:"severityNumber"`
+	SeverityText   string          `json:"severityText"`
+	Body           otlpValue       `json:"body"`
+	TraceId        string          `json:"traceId,omitempty"`
+	Attributes     []otlpAttribute `json:"attributes,omitempty"`
+}
+
+// otlpExporter collects an execution's records and ships them to an
+// OpenTelemetry collector over OTLP/HTTP with JSON encoding when the Logger
+// ends, in the background so responses never wait on the collector. Only the
+// http/json protocol is supported.
+type otlpExporter struct {
+	mutex    sync.Mutex
+	endpoint string
+	headers  map[string]string
+	timeout  time.Duration
+	resource []otlpAttribute
+	records  []otlpRecord
+	dropped  int
+}
+
+// newOtlpExporter reads the standard OTEL_EXPORTER_OTLP_* variables and
+// returns nil when no endpoint is configured.
+func newOtlpExporter(executionId string) *otlpExporter {
+	endpoint := os.Getenv("OTEL_EXPORTER_OTLP_LOGS_ENDPOINT")
+	if endpoint == "" {
+		base := os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT")
+		if base == "" {
+			return nil
+		}
+		endpoint = strings.TrimRight(base, "/") + "/v1/logs"
+	}
+
+	protocol := os.Getenv("OTEL_EXPORTER_OTLP_LOGS_PROTOCOL")
+	if protocol == "" {
+		protocol = os.Getenv("OTEL_EXPORTER_OTLP_PROTOCOL")
+	}
+	if protocol != "" && protocol != "http/json" {
+		return nil
+	}
+
+	headers := parseOtlpList(os.Getenv("OTEL_EXPORTER_OTLP_HEADERS"))
+	for key, value := range parseOtlpList(os.Getenv("OTEL_EXPORTER_OTLP_LOGS_HEADERS")) {
+		headers[key] = value
+	}
+
+	timeout := OTLP_TIMEOUT_DEFAULT
+	for _, name := range []string{"OTEL_EXPORTER_OTLP_TIMEOUT", "OTEL_EXPORTER_OTLP_LOGS_TIMEOUT"} {
+		if milliseconds, err := strconv.Atoi(os.Getenv(name)); err == nil && milliseconds > 0 {
+			timeout = time.Duration(milliseconds) * time.Millisecond
+		}
+	}
+
+	resource := parseOtlpList(os.Getenv("OTEL_RESOURCE_ATTRIBUTES"))
+	if name := os.Getenv("OTEL_SERVICE_NAME"); name != "" {
+		resource["service.name"] = name
+	}
+	if _, ok := resource["service.name"]; !ok {
+		resource["service.name"] = "open-runtimes"
+	}
+	resource["openruntimes.execution_id"] = executionId
+
+	return &otlpExporter{
+		endpoint: endpoint,
+		headers:  headers,
+		timeout:  timeout,
+		resource: otlpAttributes(stringsToAny(resource)),
+	}
+}
+
+// parseOtlpList parses the comma separated, URL encoded key=value lists used
+// by OTEL_EXPORTER_OTLP_HEADERS and OTEL_RESOURCE_ATTRIBUTES.
+func parseOtlpList(list string) map[string]string {
+	parsed := map[string]string{}
+
+	for _, pair := range strings.Split(list, ",") {
+		key, value, found := strings.Cut(pair, "=")
+		if !found {
+			continue
+		}
+
+		key, errKey := url.QueryUnescape(strings.TrimSpace(key))
+		value, errValue := url.QueryUnescape(strings.TrimSpace(value))
+		if errKey != nil || errValue != nil || key == "" {
+			continue
+		}
+
+		parsed[key] = value
+	}
+
+	return parsed
+}
+
+func stringsToAny(values map[string]string) map[string]any {
+	converted := make(map[string]any, len(values))
+	for key, value := range values {
+		converted[key] = value
+	}
+	return converted
+}
+
+func otlpAttributes(fields map[string]any) []otlpAttribute {
+	attributes := []otlpAttribute{}
+
+	for key, value := range fields {
+		attribute := otlpAttribute{Key: key}
+
+		switch v := value.(type) {
+		case string:
+			attribute.Value.StringValue = &v
+		case bool:
+			attribute.Value.BoolValue = &v
+		case int:
+			text := strconv.FormatInt(int64(v), 10)
+			attribute.Value.IntValue = &text
+		case int64:
+			text := strconv.FormatInt(v, 10)
+			attribute.Value.IntValue = &text
+		case float64:
+			attribute.Value.DoubleValue = &v
+		default:
+			text := strings.Trim(formatFieldValue(v), `"`)
+			attribute.Value.StringValue = &text
+		}
+
+		attributes = append(attributes, attribute)
+	}
+
+	return attributes
+}
+
+func (e *otlpExporter) add(xtype string, message string, fields map[string]any, traceId string) {
+	for _, line := range strings.Split(strings.TrimRight(message, "\n"), "\n") {
+		if line == "" && len(fields) == 0 {
+			continue
+		}
+
+		severity := strings.ToUpper(xtype)
+		if xtype == LOGGER_TYPE_LOG {
+			severity = "INFO"
+		}
+
+		body := line
+		record := otlpRecord{
+			TimeUnixNano:   strconv.FormatInt(time.Now().UnixNano(), 10),
+			SeverityNumber: otlpSeverities[xtype],
+			SeverityText:   severity,
+			Body:           otlpValue{StringValue: &body},
+			TraceId:        traceId,
+			Attributes:     otlpAttributes(fields),
+		}
+
+		e.mutex.Lock()
+		if len(e.records) < OTLP_MAX_RECORDS {
+			e.records = append(e.records, record)
+		} else {
+			e.dropped++
+		}
+		e.mutex.Unlock()
+	}
+}
+
+// export sends the collected records in one request from a background
+// goroutine and returns at once. Failures, and batches arriving while
+// OTLP_MAX_IN_FLIGHT exports are running, are dropped: the log files remain
+// the source of truth.
+func (e *otlpExporter) export() {
+	e.mutex.Lock()
+	records, dropped := e.records, e.dropped
+	e.records, e.dropped = nil, 0
+	e.mutex.Unlock()
+
+	if len(records) == 0 {
+		return
+	}
+
+	if dropped > 0 {
+		body := "dropped " + strconv.Itoa(dropped) + " records over the export limit"
+		records = append(records, otlpRecord{
+			TimeUnixNano:   strconv.FormatInt(time.Now().UnixNano(), 10),
+			SeverityNumber: otlpSeverities[LOGGER_TYPE_WARN],
+			SeverityText:   "WARN",
+			Body:           otlpValue{StringValue: &body},
+		})
+	}
+
+	select {
+	case otlpInFlight <- struct{}{}:
+	default:
+		return
+	}
+
+	otlpPending.Add(1)
+	go func() {
+		defer otlpPending.Done()
+		defer func() { <-otlpInFlight }()
+
+		e.send(records)
+	}()
+}
+
+// waitOtlpExports gives running exports up to timeout to finish.
+func waitOtlpExports(timeout time.Duration) {
+	done := make(chan struct{})
+	go func() {
+		otlpPending.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(timeout):
+	}
+}
+
+func (e *otlpExporter) send(records []otlpRecord) {
+	payload, err := json.Marshal(map[string]any{
+		"resourceLogs": []any{map[string]any{
+			"resource": map[string]any{"attributes": e.resource},
+			"scopeLogs": []any{map[string]any{
+				"scope":      map[string]any{"name": "open-runtimes"},
+				"logRecords": records,
+			}},
+		}},
+	})
+	if err != nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), e.timeout)
+	defer cancel()
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, e.endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return
+	}
+	request.Header.Set("Content-Type", "application/json")
+	for key, value := range e.headers {
+		request.Header.Set(key, value)
+	}
+
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		return
+	}
+	response.Body.Close()
+}
@@ -0,0 +1,55 @@
+package openruntimes
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestLoggerEndDoesNotWaitForCollector(t *testing.T) {
+	release := make(chan struct{})
+	received := make(chan int, 1)
+
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload struct {
+			ResourceLogs []struct {
+				ScopeLogs []struct {
+					LogRecords []json.RawMessage `json:"logRecords"`
+				} `json:"scopeLogs"`
+			} `json:"resourceLogs"`
+		}
+		body, _ := io.ReadAll(r.Body)
+		json.Unmarshal(body, &payload)
+
+		<-release
+		received <- len(payload.ResourceLogs[0].ScopeLogs[0].LogRecords)
+	}))
+	defer collector.Close()
+	defer close(release)
+
+	t.Setenv("OTEL_EXPORTER_OTLP_LOGS_ENDPOINT", collector.URL)
+
+	logger, _ := newFileLogger(t, LoggerOptions{})
+	for i := 0; i < OTLP_MAX_RECORDS+10; i++ {
+		logger.Info("record")
+	}
+
+	started := time.Now()
+	logger.End()
+	if elapsed := time.Since(started); elapsed > time.Second {
+		t.Fatalf("End() took %v waiting for the collector", elapsed)
+	}
+
+	release <- struct{}{}
+	select {
+	case count := <-received:
+		if count != OTLP_MAX_RECORDS+1 {
+			t.Errorf("exported %d records, want %d and a dropped notice", count, OTLP_MAX_RECORDS)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("collector never received the export")
+	}
+}
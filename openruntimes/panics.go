@@ -0,0 +1,79 @@
+package openruntimes
+
+import (
+	"fmt"
+	"runtime/debug"
+)
+
+// watchCrashes points the runtime's fatal error output, which bypasses
+// os.Stderr and ends the process before RevertNativeLogs runs, at the errors
+// log file. The runtime keeps one crash output per process, so with
+// concurrent executions the most recent one receives it.
+func (l *Logger) watchCrashes() {
+	if l.StreamErrors == nil {
+		return
+	}
+
+	debug.SetCrashOutput(l.StreamErrors, debug.CrashOptions{})
+}
+
+func (l *Logger) unwatchCrashes() {
+	if l.StreamErrors == nil {
+		return
+	}
+
+	debug.SetCrashOutput(nil, debug.CrashOptions{})
+}
+
+// writePanic records a recovered panic and its stack in the errors log and
+// flushes buffered output right away, in case the process does not survive.
+// A reference, when given, ties the entry to the response the client got.
+// Unlike writeLine, it is never dropped by sampling.
+func (l *Logger) writePanic(recovered any, stack []byte, reference string) {
+	message := fmt.Sprintf("panic: %v\n\n%s", recovered, stack)
+	if reference != "" {
+		message = "[" + reference + "] " + message
+	}
+
+	func() {
+		defer l.lock()()
+
+		l.write([]interface{}{message}, LOGGER_TYPE_ERROR, false)
+		l.write([]interface{}{"\n"}, LOGGER_TYPE_ERROR, false)
+	}()
+	l.Flush()
+
+	if l.StreamErrors != nil {
+		l.StreamErrors.Sync()
+	}
+}
+
+// Recover runs handler and turns a panic into a 500 response carrying a
+// reference id; the panic and its stack go to the error log under the same id.
+func (c *Context) Recover(handler func() Response) (response Response) {
+	defer func() {
+		recovered := recover()
+		if recovered == nil {
+			return
+		}
+
+		reference := randomHex(8)
+		c.logger.writePanic(recovered, debug.Stack(), reference)
+		runPanicHooks(c, recovered)
+
+		response = c.Res.Json(map[string]interface{}{
+			"errors": []map[string]string{{"message": "Internal Server Error", "reference": reference}},
+		}, c.Res.WithStatusCode(500))
+	}()
+
+	return handler()
+}
+
+// SafeHandler is Recover as middleware.
+func SafeHandler(next Handler) Handler {
+	return func(c *Context) Response {
+		return c.Recover(func() Response {
+			return next(c)
+		})
+	}
+}
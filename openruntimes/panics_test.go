@@ -0,0 +1,85 @@
+package openruntimes
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestWritePanicFlushesAsyncLogger(t *testing.T) {
+	logger, dir := newFileLogger(t, LoggerOptions{Async: true})
+	defer logger.End()
+
+	logger.writePanic("boom", []byte("goroutine 1 [running]:"), "ref123")
+
+	errors := readLog(t, dir, "_errors.log")
+	for _, want := range []string{"[ref123] panic: boom", "goroutine 1 [running]:"} {
+		if !strings.Contains(errors, want) {
+			t.Errorf("errors = %q, want %q before End", errors, want)
+		}
+	}
+}
+
+func TestRecover(t *testing.T) {
+	logger, dir := newFileLogger(t, LoggerOptions{})
+	c := NewContext(logger)
+
+	response := c.Recover(func() Response {
+		panic(errors.New("handler failed"))
+	})
+	logger.End()
+
+	if response.StatusCode != 500 {
+		t.Errorf("status = %d, want 500", response.StatusCode)
+	}
+	if !strings.Contains(string(response.Body), `"reference"`) {
+		t.Errorf("body = %s, want a reference", response.Body)
+	}
+	if errors := readLog(t, dir, "_errors.log"); !strings.Contains(errors, "panic: handler failed") {
+		t.Errorf("errors = %q, want the panic", errors)
+	}
+}
+
+func TestServerRecordsPanicAndNativeErrors(t *testing.T) {
+	dir := t.TempDir()
+	server := NewServer(func(c *Context) Response {
+		c.Error("before panic")
+		panic("handler crashed")
+	})
+	server.LoggerOptions = LoggerOptions{Dir: dir}
+
+	response, _ := server.Invoke(ContextRequest{
+		Method:  "GET",
+		Path:    "/",
+		Url:     "/",
+		Headers: map[string]string{HEADER_LOG_ID: "test"},
+	})
+
+	if response.StatusCode != 500 {
+		t.Errorf("status = %d, want 500", response.StatusCode)
+	}
+
+	errors := readLog(t, dir, "_errors.log")
+	for _, want := range []string{"before panic", "panic: handler crashed"} {
+		if !strings.Contains(errors, want) {
+			t.Errorf("errors = %q, want %q", errors, want)
+		}
+	}
+}
+
+func TestWritePanicBypassesSampling(t *testing.T) {
+	logger, dir := newFileLogger(t, LoggerOptions{MaxLinesPerSecond: 1})
+
+	logger.writeLine([]interface{}{"first"}, LOGGER_TYPE_ERROR)
+	logger.writeLine([]interface{}{"dropped"}, LOGGER_TYPE_ERROR)
+	logger.writePanic("boom", []byte("goroutine 1 [running]:"), "")
+	logger.End()
+
+	errors := readLog(t, dir, "_errors.log")
+	if strings.Contains(errors, "dropped") {
+		t.Fatalf("errors = %q, want the sampler to drop the second line", errors)
+	}
+	if !strings.Contains(errors, "panic: boom") {
+		t.Errorf("errors = %q, want the panic despite the rate limit", errors)
+	}
+}
@@ -0,0 +1,39 @@
+package openruntimes
+
+import (
+	"strings"
+
+	"github.com/open-runtimes/types-for-go/v4/mimetypes"
+)
+
+func (r ContextRequest) IsSecure() bool {
+	scheme := strings.ToLower(r.Scheme)
+	if scheme == "https" || scheme == "wss" {
+		return true
+	}
+
+	proto, _, _ := strings.Cut(r.Header("x-forwarded-proto"), ",")
+	return strings.EqualFold(strings.TrimSpace(proto), "https")
+}
+
+func (r ContextRequest) IsJson() bool {
+	return mimetypes.IsJson(r.Header("content-type"))
+}
+
+func (r ContextRequest) IsAjax() bool {
+	return strings.EqualFold(r.Header("x-requested-with"), "XMLHttpRequest")
+}
+
+func (r ContextRequest) IsWebSocketUpgrade() bool {
+	if !strings.EqualFold(strings.TrimSpace(r.Header("upgrade")), "websocket") {
+		return false
+	}
+
+	for _, token := range strings.Split(r.Header("connection"), ",") {
+		if strings.EqualFold(strings.TrimSpace(token), "upgrade") {
+			return true
+		}
+	}
+
+	return false
+}
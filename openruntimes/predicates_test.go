@@ -0,0 +1,48 @@
+package openruntimes
+
+import "testing"
+
+func TestPredicates(t *testing.T) {
+	tests := []struct {
+		name      string
+		scheme    string
+		headers   map[string]string
+		secure    bool
+		json      bool
+		ajax      bool
+		websocket bool
+	}{
+		{name: "plain http", scheme: "http", headers: map[string]string{}},
+		{name: "https scheme", scheme: "https", headers: map[string]string{}, secure: true},
+		{name: "wss scheme", scheme: "WSS", headers: map[string]string{}, secure: true},
+		{name: "forwarded https", scheme: "http", headers: map[string]string{"x-forwarded-proto": "https, http"}, secure: true},
+		{name: "forwarded http", scheme: "http", headers: map[string]string{"x-forwarded-proto": "http, https"}},
+		{name: "json", headers: map[string]string{"content-type": "application/json; charset=utf-8"}, json: true},
+		{name: "json suffix", headers: map[string]string{"content-type": "application/problem+json"}, json: true},
+		{name: "not json", headers: map[string]string{"content-type": "text/plain"}},
+		{name: "ajax", headers: map[string]string{"x-requested-with": "xmlhttprequest"}, ajax: true},
+		{name: "not ajax", headers: map[string]string{"x-requested-with": "fetch"}},
+		{name: "websocket", headers: map[string]string{"upgrade": "WebSocket", "connection": "keep-alive, Upgrade"}, websocket: true},
+		{name: "upgrade without connection", headers: map[string]string{"upgrade": "websocket"}},
+		{name: "other upgrade", headers: map[string]string{"upgrade": "h2c", "connection": "upgrade"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := ContextRequest{Scheme: test.scheme, Headers: test.headers}
+
+			if got := req.IsSecure(); got != test.secure {
+				t.Errorf("IsSecure() = %v, want %v", got, test.secure)
+			}
+			if got := req.IsJson(); got != test.json {
+				t.Errorf("IsJson() = %v, want %v", got, test.json)
+			}
+			if got := req.IsAjax(); got != test.ajax {
+				t.Errorf("IsAjax() = %v, want %v", got, test.ajax)
+			}
+			if got := req.IsWebSocketUpgrade(); got != test.websocket {
+				t.Errorf("IsWebSocketUpgrade() = %v, want %v", got, test.websocket)
+			}
+		})
+	}
+}
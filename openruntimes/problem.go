@@ -0,0 +1,70 @@
+package openruntimes
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+)
+
+type Problem struct {
+	Type       string
+	Title      string
+	Status     int
+	Detail     string
+	Instance   string
+	Extensions map[string]interface{}
+}
+
+func (p Problem) Error() string {
+	if p.Detail != "" {
+		return p.Title + ": " + p.Detail
+	}
+	return p.Title
+}
+
+// MarshalJSON flattens Extensions next to the standard members, as RFC 7807 requires.
+func (p Problem) MarshalJSON() ([]byte, error) {
+	body := map[string]interface{}{}
+	for key, value := range p.Extensions {
+		body[key] = value
+	}
+
+	body["type"] = p.Type
+	body["title"] = p.Title
+	body["status"] = p.Status
+
+	if p.Detail != "" {
+		body["detail"] = p.Detail
+	}
+	if p.Instance != "" {
+		body["instance"] = p.Instance
+	}
+
+	return json.Marshal(body)
+}
+
+func (r ContextResponse) Problem(p Problem, optionalSetters ...ResponseOption) Response {
+	if p.Status == 0 {
+		p.Status = 500
+	}
+	if p.Type == "" {
+		p.Type = "about:blank"
+	}
+	if p.Title == "" {
+		p.Title = http.StatusText(p.Status)
+	}
+
+	var body bytes.Buffer
+	if err := json.NewEncoder(&body).Encode(p); err != nil {
+		optionalSetters = append(optionalSetters, r.WithStatusCode(500))
+		return r.Text("Error encoding JSON.", optionalSetters...)
+	}
+	body.Truncate(body.Len() - 1)
+
+	optionalSetters = append([]ResponseOption{r.WithStatusCode(p.Status)}, optionalSetters...)
+	optionalSetters = append(optionalSetters, r.WithHeaders(map[string]string{
+		"content-type": "application/problem+json",
+	}))
+
+	return r.Binary(body.Bytes(), optionalSetters...)
+}
@@ -0,0 +1,74 @@
+package openruntimes
+
+import (
+	"net/url"
+	"strings"
+)
+
+var QueryMaxParameters = 1000
+
+// ParseQueryString splits pairs off one at a time, so the parameters past
+// QueryMaxParameters are never split or allocated.
+func ParseQueryString(qs string) map[string][]string {
+	params := map[string][]string{}
+	count := 0
+
+	qs = strings.TrimPrefix(qs, "?")
+
+	for qs != "" && count < QueryMaxParameters {
+		pair := qs
+		if index := strings.IndexAny(qs, "&;"); index != -1 {
+			pair, qs = qs[:index], qs[index+1:]
+		} else {
+			qs = ""
+		}
+		if pair == "" {
+			continue
+		}
+
+		key, value, _ := strings.Cut(pair, "=")
+
+		key = unescapeQueryComponent(key)
+		if key == "" {
+			continue
+		}
+
+		params[key] = append(params[key], unescapeQueryComponent(value))
+		count++
+	}
+
+	return params
+}
+
+// unescapeQueryComponent keeps the raw text when an escape is malformed
+// instead of dropping the parameter, and never returns invalid UTF-8.
+func unescapeQueryComponent(component string) string {
+	unescaped, err := url.QueryUnescape(component)
+	if err != nil {
+		unescaped = strings.ReplaceAll(component, "+", " ")
+	}
+
+	return strings.ToValidUTF8(unescaped, "�")
+}
+
+func (r *ContextRequest) SetQueryString(qs string) {
+	r.QueryString = qs
+	r.queryValues = ParseQueryString(qs)
+	r.Query = map[string]string{}
+
+	for key, values := range r.queryValues {
+		r.Query[key] = values[0]
+	}
+}
+
+func (r ContextRequest) QueryValues(key string) []string {
+	if values, ok := r.queryValues[key]; ok {
+		return values
+	}
+
+	if value, ok := r.Query[key]; ok {
+		return []string{value}
+	}
+
+	return []string{}
+}
@@ -0,0 +1,45 @@
+package openruntimes
+
+import (
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestParseQueryString(t *testing.T) {
+	tests := []struct {
+		qs   string
+		want map[string][]string
+	}{
+		{qs: "", want: map[string][]string{}},
+		{qs: "?a=1&b=2;a=3", want: map[string][]string{"a": {"1", "3"}, "b": {"2"}}},
+		{qs: "&&a=1&&", want: map[string][]string{"a": {"1"}}},
+		{qs: "flag&=skipped&name=a+b%20c", want: map[string][]string{"flag": {""}, "name": {"a b c"}}},
+		{qs: "bad=%zz", want: map[string][]string{"bad": {"%zz"}}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.qs, func(t *testing.T) {
+			if got := ParseQueryString(test.qs); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("ParseQueryString() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestParseQueryStringStopsAtCap(t *testing.T) {
+	qs := strings.Repeat("a=1&", QueryMaxParameters*100)
+
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	values := ParseQueryString(qs)["a"]
+	runtime.ReadMemStats(&after)
+
+	if len(values) != QueryMaxParameters {
+		t.Fatalf("values = %d, want %d", len(values), QueryMaxParameters)
+	}
+	if allocated := after.TotalAlloc - before.TotalAlloc; allocated > uint64(len(qs))/4 {
+		t.Errorf("allocated %d bytes for a %d byte query, want the pairs past the cap skipped", allocated, len(qs))
+	}
+}
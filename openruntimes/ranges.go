@@ -0,0 +1,229 @@
+package openruntimes
+
+import (
+	"errors"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+var ErrRangeInvalid = errors.New("invalid range header")
+var ErrRangeNotSatisfiable = errors.New("range not satisfiable")
+var ErrRangeTooMany = errors.New("too many ranges")
+
+// RANGES_MAX is the most ranges one Range header may ask for; more are
+// refused rather than amplified into a huge multipart response.
+const RANGES_MAX = 16
+
+type ByteRange struct {
+	Start  int64
+	Length int64
+}
+
+func (b ByteRange) End() int64 {
+	return b.Start + b.Length - 1
+}
+
+func (b ByteRange) ContentRange(size int64) string {
+	return "bytes " + strconv.FormatInt(b.Start, 10) + "-" + strconv.FormatInt(b.End(), 10) + "/" + strconv.FormatInt(size, 10)
+}
+
+// Ranges returns nil without error when the request has no Range header.
+// Overlapping and adjacent ranges are merged and returned in order; a header
+// with more than RANGES_MAX ranges is refused with ErrRangeTooMany.
+func (r ContextRequest) Ranges(size int64) ([]ByteRange, error) {
+	header := strings.TrimSpace(r.Header("range"))
+	if header == "" {
+		return nil, nil
+	}
+
+	unit, spec, found := strings.Cut(header, "=")
+	if !found || strings.TrimSpace(unit) != "bytes" {
+		return nil, ErrRangeInvalid
+	}
+
+	parts := strings.Split(spec, ",")
+	if len(parts) > RANGES_MAX {
+		return nil, ErrRangeTooMany
+	}
+
+	ranges := []ByteRange{}
+	for _, part := range parts {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		startString, endString, found := strings.Cut(part, "-")
+		if !found {
+			return nil, ErrRangeInvalid
+		}
+		startString = strings.TrimSpace(startString)
+		endString = strings.TrimSpace(endString)
+
+		if startString == "" {
+			suffix, err := strconv.ParseInt(endString, 10, 64)
+			if err != nil || suffix < 0 {
+				return nil, ErrRangeInvalid
+			}
+			if suffix == 0 || size == 0 {
+				continue
+			}
+			if suffix > size {
+				suffix = size
+			}
+
+			ranges = append(ranges, ByteRange{Start: size - suffix, Length: suffix})
+			continue
+		}
+
+		start, err := strconv.ParseInt(startString, 10, 64)
+		if err != nil || start < 0 {
+			return nil, ErrRangeInvalid
+		}
+		if start >= size {
+			continue
+		}
+
+		end := size - 1
+		if endString != "" {
+			end, err = strconv.ParseInt(endString, 10, 64)
+			if err != nil || end < start {
+				return nil, ErrRangeInvalid
+			}
+			if end >= size {
+				end = size - 1
+			}
+		}
+
+		ranges = append(ranges, ByteRange{Start: start, Length: end - start + 1})
+	}
+
+	if len(ranges) == 0 {
+		return nil, ErrRangeNotSatisfiable
+	}
+
+	return mergeRanges(ranges), nil
+}
+
+func mergeRanges(ranges []ByteRange) []ByteRange {
+	sort.Slice(ranges, func(i, j int) bool {
+		return ranges[i].Start < ranges[j].Start
+	})
+
+	merged := []ByteRange{ranges[0]}
+	for _, next := range ranges[1:] {
+		last := &merged[len(merged)-1]
+		if next.Start > last.End()+1 {
+			merged = append(merged, next)
+			continue
+		}
+		if next.End() > last.End() {
+			last.Length = next.End() - last.Start + 1
+		}
+	}
+
+	return merged
+}
+
+// BinaryRange serves content honoring the request's Range header: 206 for one
+// range, multipart/byteranges for several, 416 when none is satisfiable, and a
+// plain 200 when the header is absent, malformed, asks for too many ranges, or
+// an If-Range header no longer matches the etag or last-modified header set
+// through optionalSetters.
+func (r ContextResponse) BinaryRange(content io.ReaderAt, size int64, req ContextRequest, optionalSetters ...ResponseOption) Response {
+	options := Response{}.New()
+	for _, opt := range optionalSetters {
+		opt(options)
+	}
+
+	contentType, _ := headerGet(options.Headers, "content-type")
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	headers := map[string]string{
+		"accept-ranges": "bytes",
+		"content-type":  contentType,
+	}
+
+	etag, _ := headerGet(options.Headers, "etag")
+	lastModified := time.Time{}
+	if header, _ := headerGet(options.Headers, "last-modified"); header != "" {
+		lastModified, _ = http.ParseTime(header)
+	}
+
+	ranges, err := req.Ranges(size)
+	if !req.IfRangeMatches(etag, lastModified) {
+		ranges, err = nil, nil
+	}
+
+	if err == ErrRangeNotSatisfiable {
+		headers["content-range"] = "bytes */" + strconv.FormatInt(size, 10)
+		return r.Binary([]byte{}, append(optionalSetters, r.WithHeaders(headers), r.WithStatusCode(416))...)
+	}
+
+	if err != nil || len(ranges) == 0 {
+		headers["content-length"] = strconv.FormatInt(size, 10)
+		return r.Stream(io.NewSectionReader(content, 0, size), append([]ResponseOption{r.WithHeaders(headers)}, optionalSetters...)...)
+	}
+
+	if len(ranges) == 1 {
+		headers["content-range"] = ranges[0].ContentRange(size)
+		headers["content-length"] = strconv.FormatInt(ranges[0].Length, 10)
+		return r.Stream(io.NewSectionReader(content, ranges[0].Start, ranges[0].Length), append(optionalSetters, r.WithHeaders(headers), r.WithStatusCode(206))...)
+	}
+
+	pipeReader, pipeWriter := io.Pipe()
+	writer := multipart.NewWriter(pipeWriter)
+
+	body := &rangesReader{reader: pipeReader, writer: pipeWriter, write: func() {
+		for _, byteRange := range ranges {
+			part, err := writer.CreatePart(textproto.MIMEHeader{
+				"Content-Type":  {contentType},
+				"Content-Range": {byteRange.ContentRange(size)},
+			})
+			if err != nil {
+				pipeWriter.CloseWithError(err)
+				return
+			}
+
+			if _, err := io.Copy(part, io.NewSectionReader(content, byteRange.Start, byteRange.Length)); err != nil {
+				pipeWriter.CloseWithError(err)
+				return
+			}
+		}
+
+		pipeWriter.CloseWithError(writer.Close())
+	}}
+
+	headers["content-type"] = "multipart/byteranges; boundary=" + writer.Boundary()
+	return r.Stream(body, append(optionalSetters, r.WithHeaders(headers), r.WithStatusCode(206))...)
+}
+
+// rangesReader writes the multipart body from a goroutine started on the
+// first Read, so a response replaced before it is sent leaves nothing
+// behind, and Close stops a goroutine that is still writing.
+type rangesReader struct {
+	reader *io.PipeReader
+	writer *io.PipeWriter
+	write  func()
+	once   sync.Once
+}
+
+func (r *rangesReader) Read(p []byte) (int, error) {
+	r.once.Do(func() { go r.write() })
+	return r.reader.Read(p)
+}
+
+func (r *rangesReader) Close() error {
+	r.once.Do(func() {})
+	r.writer.CloseWithError(io.ErrClosedPipe)
+	return r.reader.Close()
+}
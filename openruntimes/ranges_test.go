@@ -0,0 +1,128 @@
+package openruntimes
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRanges(t *testing.T) {
+	tests := []struct {
+		header string
+		want   []ByteRange
+		err    error
+	}{
+		{"", nil, nil},
+		{"bytes=0-99", []ByteRange{{0, 100}}, nil},
+		{"bytes=900-", []ByteRange{{900, 100}}, nil},
+		{"bytes=-100", []ByteRange{{900, 100}}, nil},
+		{"bytes=-5000", []ByteRange{{0, 1000}}, nil},
+		{"bytes=0-1999", []ByteRange{{0, 1000}}, nil},
+		{"bytes=0-9, 20-29", []ByteRange{{0, 10}, {20, 10}}, nil},
+		{"bytes=20-29, 0-9", []ByteRange{{0, 10}, {20, 10}}, nil},
+		{"bytes=0-49, 10-19, 40-99", []ByteRange{{0, 100}}, nil},
+		{"bytes=0-9, 10-19", []ByteRange{{0, 20}}, nil},
+		{"bytes=0-0, 0-0, 0-0", []ByteRange{{0, 1}}, nil},
+		{"bytes=" + strings.Repeat("0-9,", RANGES_MAX) + "0-9", nil, ErrRangeTooMany},
+		{"bytes=5000-", nil, ErrRangeNotSatisfiable},
+		{"bytes=9-1", nil, ErrRangeInvalid},
+		{"items=0-9", nil, ErrRangeInvalid},
+		{"bytes=abc", nil, ErrRangeInvalid},
+	}
+
+	for _, test := range tests {
+		t.Run(test.header, func(t *testing.T) {
+			req := ContextRequest{Headers: map[string]string{"range": test.header}}
+
+			got, err := req.Ranges(1000)
+			if !errors.Is(err, test.err) {
+				t.Fatalf("Ranges() error = %v, want %v", err, test.err)
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("Ranges() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestBinaryRangeIfRange(t *testing.T) {
+	modified := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	content := strings.NewReader(strings.Repeat("a", 100))
+
+	tests := []struct {
+		name    string
+		ifRange string
+		status  int
+	}{
+		{"no if-range", "", 206},
+		{"matching etag", `"v1"`, 206},
+		{"changed etag", `"v2"`, 200},
+		{"weak etag", `W/"v1"`, 200},
+		{"matching date", modified.Format(http.TimeFormat), 206},
+		{"changed date", modified.Add(time.Hour).Format(http.TimeFormat), 200},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := ContextRequest{Headers: map[string]string{"range": "bytes=0-9"}}
+			if test.ifRange != "" {
+				req.Headers["if-range"] = test.ifRange
+			}
+
+			response := ContextResponse{}.BinaryRange(content, 100, req, ContextResponse{}.WithHeaders(map[string]string{
+				"etag":          `"v1"`,
+				"last-modified": modified.Format(http.TimeFormat),
+			}))
+
+			if response.StatusCode != test.status {
+				t.Errorf("status = %d, want %d", response.StatusCode, test.status)
+			}
+		})
+	}
+}
+
+func TestBinaryRangeMultipartLeavesNoGoroutine(t *testing.T) {
+	content := strings.NewReader(strings.Repeat("a", 200000))
+	req := ContextRequest{Headers: map[string]string{"range": "bytes=0-9999,100000-199999"}}
+
+	before := runtime.NumGoroutine()
+
+	unread := ContextResponse{}.BinaryRange(content, 200000, req)
+	if !strings.HasPrefix(unread.Headers["content-type"], "multipart/byteranges") {
+		t.Fatalf("content-type = %q, want multipart/byteranges", unread.Headers["content-type"])
+	}
+
+	closed := ContextResponse{}.BinaryRange(content, 200000, req)
+	closed.BodyReader.Read(make([]byte, 10))
+	closed.BodyReader.(io.Closer).Close()
+
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() > before && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if after := runtime.NumGoroutine(); after > before {
+		t.Fatalf("goroutines = %d, want %d once the responses are dropped or closed", after, before)
+	}
+}
+
+func TestBinaryRangeMultipartBody(t *testing.T) {
+	content := strings.NewReader("0123456789")
+	req := ContextRequest{Headers: map[string]string{"range": "bytes=0-1,5-6"}}
+
+	response := ContextResponse{}.BinaryRange(content, 10, req)
+	body, err := response.ReadAll()
+	if err != nil {
+		t.Fatalf("ReadAll() error = %v", err)
+	}
+
+	for _, want := range []string{"bytes 0-1/10", "01", "bytes 5-6/10", "56"} {
+		if !strings.Contains(string(body), want) {
+			t.Errorf("body = %q, want %q", body, want)
+		}
+	}
+}
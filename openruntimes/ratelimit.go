@@ -0,0 +1,122 @@
+package openruntimes
+
+import (
+	"math"
+	"strconv"
+	"sync"
+	"time"
+)
+
+type RateLimitStore interface {
+	// Take removes one token from the bucket named key, refilled at rate
+	// tokens per second up to burst, and reports how long to wait when empty.
+	Take(key string, rate float64, burst int, now time.Time) (bool, time.Duration, error)
+}
+
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// RATE_LIMIT_SWEEP_INTERVAL is how often MemoryRateLimitStore drops buckets
+// that have refilled.
+const RATE_LIMIT_SWEEP_INTERVAL = time.Minute
+
+type MemoryRateLimitStore struct {
+	mutex     sync.Mutex
+	buckets   map[string]*tokenBucket
+	lastSweep time.Time
+}
+
+func NewMemoryRateLimitStore() *MemoryRateLimitStore {
+	return &MemoryRateLimitStore{
+		buckets: map[string]*tokenBucket{},
+	}
+}
+
+func (s *MemoryRateLimitStore) Take(key string, rate float64, burst int, now time.Time) (bool, time.Duration, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	// Buckets that have refilled completely hold no state worth keeping.
+	if now.Sub(s.lastSweep) >= RATE_LIMIT_SWEEP_INTERVAL {
+		for name, bucket := range s.buckets {
+			if bucket.tokens+now.Sub(bucket.last).Seconds()*rate >= float64(burst) && name != key {
+				delete(s.buckets, name)
+			}
+		}
+		s.lastSweep = now
+	}
+
+	bucket, ok := s.buckets[key]
+	if !ok {
+		bucket = &tokenBucket{tokens: float64(burst), last: now}
+		s.buckets[key] = bucket
+	}
+
+	bucket.tokens = math.Min(float64(burst), bucket.tokens+now.Sub(bucket.last).Seconds()*rate)
+	bucket.last = now
+
+	if bucket.tokens >= 1 {
+		bucket.tokens--
+		return true, 0, nil
+	}
+
+	wait := time.Duration((1 - bucket.tokens) / rate * float64(time.Second))
+	return false, wait, nil
+}
+
+// The default store lives for the whole process, so limits hold across warm
+// invocations even when the middleware is built inside the handler.
+var defaultRateLimitStore = NewMemoryRateLimitStore()
+
+// RateLimitOptions allows Limit requests per Period for each key, with bursts
+// of up to Burst (Limit by default). Key defaults to the client IP; return an
+// API key instead to limit per consumer.
+type RateLimitOptions struct {
+	Limit  int
+	Period time.Duration
+	Burst  int
+	Store  RateLimitStore
+	Key    func(*Context) string
+}
+
+func RateLimit(options RateLimitOptions) Middleware {
+	if options.Limit <= 0 {
+		options.Limit = 60
+	}
+	if options.Period == 0 {
+		options.Period = time.Minute
+	}
+	if options.Burst <= 0 {
+		options.Burst = options.Limit
+	}
+	if options.Store == nil {
+		options.Store = defaultRateLimitStore
+	}
+	if options.Key == nil {
+		options.Key = func(c *Context) string {
+			return c.Req.ClientIP()
+		}
+	}
+
+	rate := float64(options.Limit) / options.Period.Seconds()
+	namespace := strconv.Itoa(options.Limit) + "/" + options.Period.String() + "/" + strconv.Itoa(options.Burst) + ":"
+
+	return func(next Handler) Handler {
+		return func(c *Context) Response {
+			allowed, retryAfter, err := options.Store.Take(namespace+options.Key(c), rate, options.Burst, time.Now())
+			if err != nil {
+				// Fail open: an unavailable store should not take the function down.
+				c.Error("Rate limit could not reach store: " + err.Error())
+				return next(c)
+			}
+
+			if !allowed {
+				return c.Res.TooManyRequests(retryAfter)
+			}
+
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package openruntimes
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMemoryRateLimitStoreTake(t *testing.T) {
+	store := NewMemoryRateLimitStore()
+	now := time.Now()
+
+	for i := 0; i < 2; i++ {
+		if allowed, _, _ := store.Take("client", 1, 2, now); !allowed {
+			t.Fatalf("Take() #%d refused within the burst", i+1)
+		}
+	}
+
+	allowed, wait, _ := store.Take("client", 1, 2, now)
+	if allowed || wait != time.Second {
+		t.Fatalf("Take() = %v, %v, want refused for 1s", allowed, wait)
+	}
+
+	if allowed, _, _ := store.Take("client", 1, 2, now.Add(time.Second)); !allowed {
+		t.Fatal("Take() refused after the bucket refilled")
+	}
+}
+
+func TestMemoryRateLimitStoreSweepsOnInterval(t *testing.T) {
+	store := NewMemoryRateLimitStore()
+	now := time.Now()
+
+	store.Take("first", 1, 1, now)
+	store.Take("second", 1, 1, now.Add(2*time.Second))
+	if len(store.buckets) != 2 {
+		t.Fatalf("buckets = %d, want refilled ones kept until the next sweep", len(store.buckets))
+	}
+
+	store.Take("second", 1, 1, now.Add(RATE_LIMIT_SWEEP_INTERVAL+2*time.Second))
+	if _, ok := store.buckets["first"]; ok || len(store.buckets) != 1 {
+		t.Fatalf("buckets = %v, want the refilled bucket swept", store.buckets)
+	}
+}
@@ -0,0 +1,188 @@
+package openruntimes
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+const LOGGER_FORMAT_TEXT = "text"
+const LOGGER_FORMAT_JSON = "json"
+
+// loggerMutex guards Loggers built without NewLogger, which have no lock of
+// their own.
+var loggerMutex sync.Mutex
+
+// lock serialises everything that writes to the Logger's streams or changes
+// its state, and returns the matching unlock.
+func (l *Logger) lock() func() {
+	mutex := l.mutex
+	if mutex == nil {
+		mutex = &loggerMutex
+	}
+
+	mutex.Lock()
+	return mutex.Unlock
+}
+
+type logRecord struct {
+	Timestamp   string         `json:"timestamp"`
+	Level       string         `json:"level"`
+	ExecutionId string         `json:"executionId"`
+	TraceId     string         `json:"traceId,omitempty"`
+	RequestId   string         `json:"requestId,omitempty"`
+	Message     string         `json:"message"`
+	Fields      map[string]any `json:"fields,omitempty"`
+}
+
+// streamFor prefers the configured writer and falls back to the exported
+// stream, so a Logger assembled by hand keeps writing where it used to.
+func (l *Logger) streamFor(xtype string) io.Writer {
+	writer, stream := l.writerLogs, l.StreamLogs
+
+	switch xtype {
+	case LOGGER_TYPE_ERROR, LOGGER_TYPE_WARN:
+		writer, stream = l.writerErrors, l.StreamErrors
+	case LOGGER_TYPE_AUDIT:
+		writer, stream = l.writerAudit, l.StreamAudit
+	}
+
+	if writer != nil {
+		return writer
+	}
+	if stream != nil {
+		return stream
+	}
+	return io.Discard
+}
+
+// writeRecord is the single place log output leaves the Logger. In text
+// format the message is written as-is; in JSON format every call becomes one
+// line, and the bare newlines Context.Log writes after a message are dropped.
+// Everything but audit lines, which are redacted before hashing, is redacted
+// here, before truncation could cut a secret short of its pattern.
+func (l *Logger) writeRecord(xtype string, message string, fields map[string]any, terminator string) {
+	stream := l.streamFor(xtype)
+
+	if xtype == LOGGER_TYPE_AUDIT {
+		l.emit(stream, []byte(message+formatFields(fields)+terminator))
+		return
+	}
+
+	if message != "\n" || len(fields) > 0 {
+		fields = l.boundFields(fields)
+	}
+
+	message = l.truncateMessage(redactText(message))
+	fields = redactFields(fields)
+
+	if l.exporter != nil {
+		l.exporter.add(xtype, message, fields, l.traceId)
+	}
+
+	l.mirrorToConsole(xtype, message+formatFields(fields)+terminator)
+
+	if l.Format != LOGGER_FORMAT_JSON {
+		if !l.Prefix && l.requestId != "" && (strings.Trim(message, "\n") != "" || len(fields) > 0) {
+			fields = withField(fields, "requestId", l.requestId)
+		}
+		l.emit(stream, []byte(l.prefixLines(xtype, textRecord(message, fields, terminator))))
+		return
+	}
+
+	message = strings.TrimRight(message, "\n")
+	if message == "" && len(fields) == 0 {
+		return
+	}
+
+	level := xtype
+	if level == LOGGER_TYPE_LOG {
+		level = LOGGER_TYPE_INFO
+	}
+
+	line, err := json.Marshal(logRecord{
+		Timestamp:   time.Now().UTC().Format(time.RFC3339Nano),
+		Level:       level,
+		ExecutionId: l.Id,
+		TraceId:     l.traceId,
+		RequestId:   l.requestId,
+		Message:     message,
+		Fields:      fields,
+	})
+	if err != nil {
+		line, _ = json.Marshal(logRecord{
+			Timestamp:   time.Now().UTC().Format(time.RFC3339Nano),
+			Level:       level,
+			ExecutionId: l.Id,
+			TraceId:     l.traceId,
+			RequestId:   l.requestId,
+			Message:     message + formatFields(fields),
+		})
+	}
+
+	l.emit(stream, append(line, '\n'))
+}
+
+// textRecord renders a text record, keeping fields on the message's line when
+// the message, like a captured native line, ends in its own newline.
+func textRecord(message string, fields map[string]any, terminator string) string {
+	if len(fields) == 0 {
+		return message + terminator
+	}
+
+	line := strings.TrimRight(message, "\n")
+	return line + formatFields(fields) + message[len(line):] + terminator
+}
+
+// withField returns fields with key set to value unless it is already there,
+// leaving the caller's map untouched.
+func withField(fields map[string]any, key string, value any) map[string]any {
+	if _, ok := fields[key]; ok {
+		return fields
+	}
+
+	merged := make(map[string]any, len(fields)+1)
+	for existing, v := range fields {
+		merged[existing] = v
+	}
+	merged[key] = value
+
+	return merged
+}
+
+// prefixLines puts "<RFC3339 time> [<type>] [<execution id>] [<request id>] "
+// in front of every line when Prefix is on, leaving the request id out until
+// one is known; without Prefix, writeRecord adds it as a field instead.
+// Messages and their newline arrive in separate writes, so whether the stream
+// is mid-line is remembered between calls.
+func (l *Logger) prefixLines(xtype string, text string) string {
+	if !l.Prefix || text == "" {
+		return text
+	}
+
+	midLine := &l.midLineLogs
+	if xtype == LOGGER_TYPE_ERROR || xtype == LOGGER_TYPE_WARN {
+		midLine = &l.midLineErrors
+	}
+
+	prefix := time.Now().UTC().Format(time.RFC3339) + " [" + xtype + "] [" + l.Id + "] "
+	if l.requestId != "" {
+		prefix += "[" + l.requestId + "] "
+	}
+
+	var builder strings.Builder
+	for _, line := range strings.SplitAfter(text, "\n") {
+		if line == "" {
+			continue
+		}
+		if !*midLine && line != "\n" {
+			builder.WriteString(prefix)
+		}
+		builder.WriteString(line)
+		*midLine = !strings.HasSuffix(line, "\n")
+	}
+
+	return builder.String()
+}
@@ -0,0 +1,79 @@
+package openruntimes
+
+import (
+	"bytes"
+	"encoding/json"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func newBufferLogger(t *testing.T, format string, prefix bool) (*Logger, *bytes.Buffer) {
+	t.Helper()
+
+	var logs bytes.Buffer
+	logger := &Logger{
+		Enabled:    true,
+		Format:     format,
+		Prefix:     prefix,
+		Id:         "exec",
+		requestId:  "req-1",
+		writerLogs: &logs,
+	}
+	return logger, &logs
+}
+
+func TestRequestIdInTextRecords(t *testing.T) {
+	logger, logs := newBufferLogger(t, LOGGER_FORMAT_TEXT, false)
+
+	logger.writeLine([]interface{}{"hello"}, LOGGER_TYPE_LOG)
+	logger.Write([]interface{}{"native line\n"}, LOGGER_TYPE_LOG, true)
+	logger.WriteWith("with fields", map[string]any{"requestId": "own"}, LOGGER_TYPE_LOG)
+
+	for _, want := range []string{"hello requestId=req-1\n", "native line requestId=req-1\n", "with fields requestId=own\n"} {
+		if !strings.Contains(logs.String(), want) {
+			t.Errorf("logs = %q, want %q", logs.String(), want)
+		}
+	}
+}
+
+func TestRequestIdInPrefixedRecords(t *testing.T) {
+	logger, logs := newBufferLogger(t, LOGGER_FORMAT_TEXT, true)
+
+	logger.writeLine([]interface{}{"hello"}, LOGGER_TYPE_LOG)
+
+	if !strings.HasSuffix(logs.String(), " [log] [exec] [req-1] hello\n") {
+		t.Errorf("logs = %q, want the prefix to carry the request id", logs.String())
+	}
+	if strings.Contains(logs.String(), "requestId=") {
+		t.Errorf("logs = %q, request id repeated as a field", logs.String())
+	}
+}
+
+func TestRequestIdInJsonRecords(t *testing.T) {
+	logger, logs := newBufferLogger(t, LOGGER_FORMAT_JSON, false)
+
+	logger.writeLine([]interface{}{"hello"}, LOGGER_TYPE_LOG)
+
+	var record logRecord
+	if err := json.Unmarshal(logs.Bytes(), &record); err != nil {
+		t.Fatalf("logs = %q: %v", logs.String(), err)
+	}
+	if record.RequestId != "req-1" || record.Message != "hello" {
+		t.Errorf("record = %+v", record)
+	}
+}
+
+func TestRedactionRunsBeforeTruncation(t *testing.T) {
+	RegisterRedactionPattern(regexp.MustCompile(`sk-test-[0-9]{8}`))
+
+	logger, logs := newBufferLogger(t, LOGGER_FORMAT_TEXT, false)
+	logger.requestId = ""
+	logger.maxMessageBytes = 12
+
+	logger.writeLine([]interface{}{"key sk-test-12345678"}, LOGGER_TYPE_LOG)
+
+	if strings.Contains(logs.String(), "sk-test") {
+		t.Fatalf("logs = %q, want the secret redacted before truncation", logs.String())
+	}
+}
@@ -0,0 +1,97 @@
+package openruntimes
+
+import (
+	"regexp"
+	"strings"
+	"sync"
+)
+
+const REDACTED = "[REDACTED]"
+
+var (
+	redactionMutex    sync.RWMutex
+	redactedFields    = map[string]bool{"authorization": true, "password": true, "token": true}
+	redactionPatterns = []*regexp.Regexp{}
+	redactedAssigns   = compileRedactedAssigns(redactedFields)
+)
+
+// RegisterRedactedFields masks the value of fields with these names, matched
+// case-insensitively, both in structured fields and in "name=value" or
+// "name: value" text.
+func RegisterRedactedFields(names ...string) {
+	redactionMutex.Lock()
+	defer redactionMutex.Unlock()
+
+	for _, name := range names {
+		redactedFields[strings.ToLower(name)] = true
+	}
+	redactedAssigns = compileRedactedAssigns(redactedFields)
+}
+
+// RegisterRedactionPattern masks every match of pattern in log output.
+func RegisterRedactionPattern(pattern *regexp.Regexp) {
+	redactionMutex.Lock()
+	defer redactionMutex.Unlock()
+
+	redactionPatterns = append(redactionPatterns, pattern)
+}
+
+func compileRedactedAssigns(fields map[string]bool) *regexp.Regexp {
+	names := []string{}
+	for name := range fields {
+		names = append(names, regexp.QuoteMeta(name))
+	}
+
+	return regexp.MustCompile(`(?i)("?\b(?:` + strings.Join(names, "|") + `)\b"?\s*[:=]\s*"?(?:(?:Bearer|Basic)\s+)?)[^"\s,&;]+`)
+}
+
+func isRedactedField(name string) bool {
+	redactionMutex.RLock()
+	defer redactionMutex.RUnlock()
+
+	return redactedFields[strings.ToLower(name)]
+}
+
+// redactText masks registered field assignments and patterns in text.
+func redactText(text string) string {
+	if text == "" {
+		return text
+	}
+
+	redactionMutex.RLock()
+	defer redactionMutex.RUnlock()
+
+	text = redactedAssigns.ReplaceAllString(text, "${1}"+REDACTED)
+	for _, pattern := range redactionPatterns {
+		text = pattern.ReplaceAllString(text, REDACTED)
+	}
+
+	return text
+}
+
+// redactFields returns a copy of fields with registered names masked and
+// string values passed through redactText. Nested maps are handled too.
+func redactFields(fields map[string]any) map[string]any {
+	if len(fields) == 0 {
+		return fields
+	}
+
+	redacted := make(map[string]any, len(fields))
+	for key, value := range fields {
+		if isRedactedField(key) {
+			redacted[key] = REDACTED
+			continue
+		}
+
+		switch v := value.(type) {
+		case string:
+			redacted[key] = redactText(v)
+		case map[string]any:
+			redacted[key] = redactFields(v)
+		default:
+			redacted[key] = value
+		}
+	}
+
+	return redacted
+}
@@ -0,0 +1,157 @@
+package openruntimes
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+)
+
+type NonceStore interface {
+	// Remember stores the nonce until expiresAt and reports false if it was already seen.
+	Remember(nonce string, expiresAt time.Time) (bool, error)
+}
+
+// REPLAY_SWEEP_INTERVAL is how often MemoryNonceStore drops expired nonces.
+const REPLAY_SWEEP_INTERVAL = time.Minute
+
+// REPLAY_LOG_NONCE_MAX caps how much of a client-supplied nonce reaches the logs.
+const REPLAY_LOG_NONCE_MAX = 64
+
+type MemoryNonceStore struct {
+	mutex     sync.Mutex
+	nonces    map[string]time.Time
+	lastSweep time.Time
+}
+
+func NewMemoryNonceStore() *MemoryNonceStore {
+	return &MemoryNonceStore{
+		nonces: map[string]time.Time{},
+	}
+}
+
+func (s *MemoryNonceStore) Remember(nonce string, expiresAt time.Time) (bool, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	now := time.Now()
+	if now.Sub(s.lastSweep) >= REPLAY_SWEEP_INTERVAL {
+		for key, expiry := range s.nonces {
+			if now.After(expiry) {
+				delete(s.nonces, key)
+			}
+		}
+		s.lastSweep = now
+	}
+
+	if expiry, ok := s.nonces[nonce]; ok && !now.After(expiry) {
+		return false, nil
+	}
+
+	s.nonces[nonce] = expiresAt
+	return true, nil
+}
+
+type ReplayOptions struct {
+	Secret          []byte
+	Tolerance       time.Duration
+	Store           NonceStore
+	TimestampHeader string
+	NonceHeader     string
+	SignatureHeader string
+}
+
+// ReplayProtection panics without a Secret: an HMAC with an empty key is one
+// anyone can compute, so the middleware would accept forged deliveries.
+func ReplayProtection(options ReplayOptions) Middleware {
+	if len(options.Secret) == 0 {
+		panic("openruntimes: ReplayProtection requires a non-empty Secret")
+	}
+	if options.Tolerance == 0 {
+		options.Tolerance = 5 * time.Minute
+	}
+	if options.Store == nil {
+		options.Store = NewMemoryNonceStore()
+	}
+	if options.TimestampHeader == "" {
+		options.TimestampHeader = "x-timestamp"
+	}
+	if options.NonceHeader == "" {
+		options.NonceHeader = "x-nonce"
+	}
+	if options.SignatureHeader == "" {
+		options.SignatureHeader = "x-signature"
+	}
+
+	return func(next Handler) Handler {
+		return func(c *Context) Response {
+			timestamp := c.Req.Header(options.TimestampHeader)
+			nonce := c.Req.Header(options.NonceHeader)
+			signature := c.Req.Header(options.SignatureHeader)
+
+			if timestamp == "" || nonce == "" || signature == "" {
+				c.Error("Replay protection rejected request: missing signature headers.")
+				return c.Res.Unauthorized()
+			}
+
+			expected := SignReplay(options.Secret, timestamp, nonce, c.Req.BodyBinary())
+			if !hmac.Equal([]byte(signature), []byte(expected)) {
+				c.Error("Replay protection rejected request: invalid signature.")
+				return c.Res.Unauthorized()
+			}
+
+			sentAt, err := parseReplayTimestamp(timestamp)
+			if err != nil {
+				c.Error("Replay protection rejected request: " + err.Error() + ".")
+				return c.Res.Unauthorized()
+			}
+
+			age := time.Since(sentAt)
+			if age > options.Tolerance || age < -options.Tolerance {
+				c.Error("Replay protection rejected request: timestamp outside tolerance.")
+				return c.Res.Unauthorized()
+			}
+
+			fresh, err := options.Store.Remember(nonce, sentAt.Add(options.Tolerance))
+			if err != nil {
+				c.Error("Replay protection could not store nonce: " + err.Error())
+				return c.Res.InternalError(err)
+			}
+
+			if !fresh {
+				c.Error("Replay protection rejected request: nonce " + quoteForLog(nonce, REPLAY_LOG_NONCE_MAX) + " already used.")
+				return c.Res.Conflict()
+			}
+
+			return next(c)
+		}
+	}
+}
+
+func SignReplay(secret []byte, timestamp string, nonce string, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(timestamp + "." + nonce + "."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func parseReplayTimestamp(timestamp string) (time.Time, error) {
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return time.Time{}, errors.New("invalid timestamp")
+	}
+
+	return time.Unix(seconds, 0), nil
+}
+
+// quoteForLog quotes a client-supplied value, escaping control characters so
+// it cannot forge log lines, after cutting it to max bytes.
+func quoteForLog(value string, max int) string {
+	if len(value) > max {
+		return strconv.Quote(value[:max]) + "…"
+	}
+	return strconv.Quote(value)
+}
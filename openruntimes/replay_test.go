@@ -0,0 +1,99 @@
+package openruntimes
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func signedReplayRequest(secret []byte, sentAt time.Time, nonce string, body string) ContextRequest {
+	timestamp := strconv.FormatInt(sentAt.Unix(), 10)
+
+	req := ContextRequest{Headers: map[string]string{
+		"x-timestamp": timestamp,
+		"x-nonce":     nonce,
+		"x-signature": SignReplay(secret, timestamp, nonce, []byte(body)),
+	}}
+	req.SetBodyBinary([]byte(body))
+	return req
+}
+
+func TestReplayProtection(t *testing.T) {
+	secret := []byte("webhook-secret")
+	handler := ReplayProtection(ReplayOptions{Secret: secret})(func(c *Context) Response {
+		return c.Res.Text("ok")
+	})
+
+	forged := signedReplayRequest([]byte("other"), time.Now(), "n-forged", "{}")
+	missing := signedReplayRequest(secret, time.Now(), "n-missing", "{}")
+	delete(missing.Headers, "x-signature")
+	tampered := signedReplayRequest(secret, time.Now(), "n-tampered", "{}")
+	tampered.SetBodyBinary([]byte(`{"amount":1000}`))
+
+	tests := []struct {
+		name   string
+		req    ContextRequest
+		status int
+	}{
+		{"valid", signedReplayRequest(secret, time.Now(), "n-1", "{}"), 200},
+		{"replayed nonce", signedReplayRequest(secret, time.Now(), "n-1", "{}"), 409},
+		{"wrong secret", forged, 401},
+		{"missing signature", missing, 401},
+		{"tampered body", tampered, 401},
+		{"stale timestamp", signedReplayRequest(secret, time.Now().Add(-time.Hour), "n-2", "{}"), 401},
+		{"future timestamp", signedReplayRequest(secret, time.Now().Add(time.Hour), "n-3", "{}"), 401},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := NewContext(Logger{})
+			c.Req = test.req
+
+			response := handler(&c)
+			if response.StatusCode != test.status {
+				t.Errorf("status = %d, want %d", response.StatusCode, test.status)
+			}
+			if test.status != 200 && !strings.HasPrefix(string(response.Body), `{"errors":`) {
+				t.Errorf("body = %s, want an errorBody", response.Body)
+			}
+		})
+	}
+}
+
+func TestReplayProtectionRequiresSecret(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("ReplayProtection() accepted an empty Secret")
+		}
+	}()
+
+	ReplayProtection(ReplayOptions{})
+}
+
+func TestMemoryNonceStore(t *testing.T) {
+	store := NewMemoryNonceStore()
+
+	if fresh, _ := store.Remember("a", time.Now().Add(time.Minute)); !fresh {
+		t.Fatal("first use reported as seen")
+	}
+	if fresh, _ := store.Remember("a", time.Now().Add(time.Minute)); fresh {
+		t.Fatal("second use reported as fresh")
+	}
+
+	store.Remember("b", time.Now().Add(-time.Second))
+	if fresh, _ := store.Remember("b", time.Now().Add(time.Minute)); !fresh {
+		t.Fatal("expired nonce reported as seen")
+	}
+}
+
+func TestQuoteForLog(t *testing.T) {
+	quoted := quoteForLog("abc\n2024-01-01 [error] forged"+strings.Repeat("x", 100), 32)
+
+	if strings.Contains(quoted, "\n") {
+		t.Errorf("quoteForLog() kept a newline: %q", quoted)
+	}
+	if len(quoted) > 32+8 {
+		t.Errorf("quoteForLog() = %d bytes, want about 32", len(quoted))
+	}
+}
@@ -0,0 +1,112 @@
+package openruntimes
+
+import (
+	"errors"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const LOGS_TRUNCATED_MARKER = "\n[logs truncated: size limit reached]\n"
+
+// limitedWriter caps how many bytes one execution may write to a stream. File
+// streams rotate into numbered files until MaxFiles is used up; after that,
+// and for plain writers, output is cut with LOGS_TRUNCATED_MARKER.
+type limitedWriter struct {
+	mutex     sync.Mutex
+	target    io.Writer
+	file      *os.File
+	path      string
+	maxBytes  int64
+	maxFiles  int
+	written   int64
+	rotations int
+	truncated bool
+}
+
+func newLimitedWriter(target io.Writer, file *os.File, options LoggerOptions) io.Writer {
+	if options.MaxBytes <= 0 {
+		return target
+	}
+
+	writer := &limitedWriter{
+		target:   target,
+		file:     file,
+		maxBytes: options.MaxBytes,
+		maxFiles: options.MaxFiles,
+	}
+	if file != nil {
+		writer.path = file.Name()
+	}
+
+	return writer
+}
+
+func (w *limitedWriter) Write(p []byte) (int, error) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	total := len(p)
+
+	for len(p) > 0 && !w.truncated {
+		remaining := w.maxBytes - w.written
+		if int64(len(p)) <= remaining {
+			n, err := w.target.Write(p)
+			w.written += int64(n)
+			if err != nil {
+				return total, err
+			}
+			break
+		}
+
+		if remaining > 0 {
+			w.target.Write(p[:remaining])
+			p = p[remaining:]
+		}
+
+		if w.file != nil && w.rotations+1 < w.maxFiles {
+			if err := w.rotate(); err != nil {
+				return total, err
+			}
+			continue
+		}
+
+		w.target.Write([]byte(LOGS_TRUNCATED_MARKER))
+		w.truncated = true
+	}
+
+	return total, nil
+}
+
+func (w *limitedWriter) rotate() error {
+	w.rotations++
+
+	path := strings.TrimSuffix(w.path, ".log") + "." + strconv.Itoa(w.rotations) + ".log"
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY|os.O_CREATE, 0644)
+	if err != nil {
+		return errors.New("could not rotate log file")
+	}
+
+	w.file.Sync()
+	w.file.Close()
+
+	w.file = file
+	w.target = file
+	w.written = 0
+
+	return nil
+}
+
+func (w *limitedWriter) Close() error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if w.file == nil {
+		return nil
+	}
+
+	w.file.Sync()
+	return w.file.Close()
+}
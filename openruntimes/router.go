@@ -0,0 +1,226 @@
+package openruntimes
+
+import (
+	"net/url"
+	"strings"
+)
+
+const METHOD_ANY = "*"
+
+type route struct {
+	pattern  string
+	segments []string
+	handlers map[string]Handler
+}
+
+// Router dispatches on the request method and path, so one function can
+// serve a small REST API. Patterns are made of static segments, parameters
+// such as ":id", and an optional trailing wildcard "*name" that captures the
+// rest of the path. Static segments win over parameters, parameters over
+// wildcards.
+type Router struct {
+	table      *routeTable
+	prefix     string
+	middleware []Middleware
+}
+
+// routeTable is shared by a Router and its groups.
+type routeTable struct {
+	routes []*route
+}
+
+func NewRouter() *Router {
+	return &Router{table: &routeTable{}}
+}
+
+// Group returns a Router for the subtree under prefix. Routes registered on
+// it get the prefix and run behind middleware, after any the parent group
+// already applies; Serve on either dispatches the whole tree.
+func (r *Router) Group(prefix string, middleware ...Middleware) *Router {
+	if r.table == nil {
+		r.table = &routeTable{}
+	}
+
+	return &Router{
+		table:      r.table,
+		prefix:     r.prefix + "/" + strings.Trim(prefix, "/"),
+		middleware: append(append([]Middleware{}, r.middleware...), middleware...),
+	}
+}
+
+// splitPath ignores empty segments, so "/a//b/" is treated as "/a/b".
+func splitPath(path string) []string {
+	segments := []string{}
+	for _, segment := range strings.Split(path, "/") {
+		if segment != "" {
+			segments = append(segments, segment)
+		}
+	}
+	return segments
+}
+
+// Handle registers handler for method and pattern; METHOD_ANY matches every
+// method not registered explicitly.
+func (r *Router) Handle(method string, pattern string, handler Handler) {
+	if r.table == nil {
+		r.table = &routeTable{}
+	}
+
+	method = strings.ToUpper(method)
+	segments := splitPath(r.prefix + "/" + pattern)
+	normalized := "/" + strings.Join(segments, "/")
+	handler = Chain(r.middleware...)(handler)
+
+	for _, existing := range r.table.routes {
+		if existing.pattern == normalized {
+			existing.handlers[method] = handler
+			return
+		}
+	}
+
+	r.table.routes = append(r.table.routes, &route{
+		pattern:  normalized,
+		segments: segments,
+		handlers: map[string]Handler{method: handler},
+	})
+}
+
+// match returns the captured parameters and a score where each static
+// segment counts more than any number of parameters.
+func (rt *route) match(segments []string) (map[string]string, int, bool) {
+	params := map[string]string{}
+	score := 0
+
+	for i, part := range rt.segments {
+		if strings.HasPrefix(part, "*") {
+			rest := strings.Join(segments[min(i, len(segments)):], "/")
+			if name := part[1:]; name != "" {
+				params[name] = rest
+			}
+			return params, score, true
+		}
+
+		if i >= len(segments) {
+			return nil, 0, false
+		}
+
+		switch {
+		case strings.HasPrefix(part, ":"):
+			value, err := url.PathUnescape(segments[i])
+			if err != nil {
+				value = segments[i]
+			}
+			params[part[1:]] = value
+			score += 1
+		case part == segments[i]:
+			score += 1000
+		default:
+			return nil, 0, false
+		}
+	}
+
+	if len(segments) != len(rt.segments) {
+		return nil, 0, false
+	}
+
+	// Exact matches beat wildcard matches of the same static prefix.
+	return params, score + 1, true
+}
+
+func (r *Router) lookup(path string) (*route, map[string]string) {
+	if r.table == nil {
+		return nil, nil
+	}
+
+	segments := splitPath(path)
+
+	var best *route
+	var bestParams map[string]string
+	bestScore := -1
+
+	for _, rt := range r.table.routes {
+		params, score, ok := rt.match(segments)
+		if ok && score > bestScore {
+			best, bestParams, bestScore = rt, params, score
+		}
+	}
+
+	return best, bestParams
+}
+
+func (rt *route) methods() []string {
+	methods := []string{}
+	for method := range rt.handlers {
+		if method != METHOD_ANY {
+			methods = append(methods, method)
+		}
+	}
+	return methods
+}
+
+// Serve is the Router's Handler: it fills ctx.Req.Params() and answers 404
+// when no pattern matches the path and 405 when the method does not.
+// HEAD falls back to GET, and OPTIONS is answered from the registered
+// methods, unless either is handled explicitly.
+func (r *Router) Serve(c *Context) Response {
+	rt, params := r.lookup(c.Req.Path)
+	if rt == nil {
+		return c.Res.NotFound()
+	}
+
+	method := strings.ToUpper(c.Req.Method)
+
+	handler, ok := rt.handlers[method]
+	if !ok && method == "HEAD" {
+		handler, ok = rt.handlers["GET"]
+	}
+	if !ok {
+		handler, ok = rt.handlers[METHOD_ANY]
+	}
+	if !ok {
+		metadata := RouteMetadata{Methods: rt.methods()}
+		if strings.EqualFold(c.Req.Method, "OPTIONS") {
+			return c.Res.Capabilities(metadata)
+		}
+		return c.Res.MethodNotAllowed(metadata.Allow())
+	}
+
+	c.Req.params = params
+	return handler(c)
+}
+
+// Params holds the values captured by the Router for the matched pattern.
+func (r ContextRequest) Params() map[string]string {
+	if r.params == nil {
+		return map[string]string{}
+	}
+	return r.params
+}
+
+func (r ContextRequest) Param(name string) string {
+	return r.params[name]
+}
+
+func (r *Router) Get(pattern string, handler Handler) {
+	r.Handle("GET", pattern, handler)
+}
+
+func (r *Router) Post(pattern string, handler Handler) {
+	r.Handle("POST", pattern, handler)
+}
+
+func (r *Router) Put(pattern string, handler Handler) {
+	r.Handle("PUT", pattern, handler)
+}
+
+func (r *Router) Patch(pattern string, handler Handler) {
+	r.Handle("PATCH", pattern, handler)
+}
+
+func (r *Router) Delete(pattern string, handler Handler) {
+	r.Handle("DELETE", pattern, handler)
+}
+
+func (r *Router) Any(pattern string, handler Handler) {
+	r.Handle(METHOD_ANY, pattern, handler)
+}
@@ -0,0 +1,82 @@
+package openruntimes
+
+import (
+	"strconv"
+	"time"
+)
+
+// logSampler keeps every SampleEvery-th debug message and at most
+// MaxLinesPerSecond messages overall. It is only used under the Logger lock.
+type logSampler struct {
+	sampleEvery  int
+	maxPerSecond int
+	debugSeen    int
+	windowStart  time.Time
+	windowCount  int
+	suppressed   int
+}
+
+func newLogSampler(options LoggerOptions) *logSampler {
+	if options.SampleEvery <= 1 && options.MaxLinesPerSecond <= 0 {
+		return nil
+	}
+
+	return &logSampler{
+		sampleEvery:  options.SampleEvery,
+		maxPerSecond: options.MaxLinesPerSecond,
+	}
+}
+
+func (s *logSampler) allow(xtype string, now time.Time) bool {
+	if xtype == LOGGER_TYPE_DEBUG && s.sampleEvery > 1 {
+		s.debugSeen++
+		if (s.debugSeen-1)%s.sampleEvery != 0 {
+			s.suppressed++
+			return false
+		}
+	}
+
+	if s.maxPerSecond > 0 {
+		if now.Sub(s.windowStart) >= time.Second {
+			s.windowStart = now
+			s.windowCount = 0
+		}
+
+		if s.windowCount >= s.maxPerSecond {
+			s.suppressed++
+			return false
+		}
+		s.windowCount++
+	}
+
+	return true
+}
+
+// sample reports whether a message of xtype should be written. Before the
+// first message let through after a suppression, a summary line is written.
+func (l *Logger) sample(xtype string) bool {
+	if l.sampler == nil {
+		return true
+	}
+
+	if !l.sampler.allow(xtype, time.Now()) {
+		if l.counters != nil {
+			l.counters.suppressed.Add(1)
+		}
+		return false
+	}
+
+	l.writeSuppressed()
+	return true
+}
+
+func (l *Logger) writeSuppressed() {
+	if l.sampler == nil || l.sampler.suppressed == 0 {
+		return
+	}
+
+	suppressed := l.sampler.suppressed
+	l.sampler.suppressed = 0
+
+	l.writeRecord(LOGGER_TYPE_INFO, "suppressed "+strconv.Itoa(suppressed)+" messages", nil, "\n")
+}
@@ -0,0 +1,256 @@
+package openruntimes
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const HEADER_LOGGING = "x-open-runtimes-logging"
+const HEADER_LOG_ID = "x-open-runtimes-log-id"
+
+type Server struct {
+	Handler             Handler
+	ShutdownGracePeriod time.Duration
+	StrictResponses     bool
+	LoggerOptions       LoggerOptions
+}
+
+func NewServer(handler Handler) Server {
+	return Server{
+		Handler:             handler,
+		ShutdownGracePeriod: SHUTDOWN_GRACE_PERIOD,
+		StrictResponses:     IsStrictMode(),
+	}
+}
+
+// Invoke runs one execution: it prepares the Logger from the executor headers,
+// captures native output unless another execution already is, calls the handler and returns its response together
+// with the logger that received the execution's logs.
+//
+// A streamed response keeps the execution open, with its context live and its
+// logs captured, until the body has been read to the end or closed. Callers
+// must do one or the other, as Response.ReadAll does.
+func (s Server) Invoke(req ContextRequest) (Response, Logger) {
+	return s.InvokeContext(context.Background(), req)
+}
+
+// InvokeContext is Invoke with a parent context, such as the incoming HTTP
+// request's. The handler sees it through Context.Context, bounded by the
+// x-open-runtimes-timeout header, and it is cancelled once the execution is
+// over, which for streams is when the body is finished. If parent is
+// cancelled first, as when an HTTP client disconnects, the cancellation cause
+// is ErrClientDisconnected.
+func (s Server) InvokeContext(parent context.Context, req ContextRequest) (Response, Logger) {
+	response, logger, finish := s.invoke(parent, req)
+
+	if !response.IsStream() {
+		finish()
+		return response, *logger
+	}
+
+	response.BodyReader = &finishingReader{reader: response.BodyReader, finish: finish}
+	return response, *logger
+}
+
+// nativeCapture is held by the execution whose Logger has os.Stdout and
+// os.Stderr swapped for pipes. They are process-wide, so executions running
+// alongside it leave native output uncaptured rather than swap them again.
+var nativeCapture sync.Mutex
+
+// invoke runs the handler and returns finish, which ends the execution:
+// it restores native output, closes the Logger and cancels the context.
+// Streamed bodies must be written before finish is called.
+func (s Server) invoke(parent context.Context, req ContextRequest) (Response, *Logger, func()) {
+	ctx, cancel := withExecutionTimeout(parent, req)
+
+	logger, err := NewLoggerWithOptions(req.Header(HEADER_LOGGING), req.Header(HEADER_LOG_ID), s.LoggerOptions)
+	if err != nil {
+		logger, _ = NewLogger("disabled", "")
+	}
+
+	execution := executionFromRequest(req, logger.Id)
+
+	req = req.Clone()
+	req.stripHeaders("x-open-runtimes-")
+
+	context := NewContext(logger)
+	context.Req = req
+	context.SetContext(ctx)
+	context.bindTrace()
+	context.execution = &execution
+
+	captured := false
+	if logger.Enabled && nativeCapture.TryLock() {
+		if err := context.logger.OverrideNativeLogs(); err != nil {
+			context.Error(err.Error())
+			nativeCapture.Unlock()
+		} else {
+			captured = true
+		}
+	}
+
+	response := s.run(&context)
+
+	if s.StrictResponses {
+		if err := response.Validate(); err != nil {
+			context.Error(err.Error())
+			response = context.Res.Text("", context.Res.WithStatusCode(500))
+		}
+	}
+
+	runFinishHooks(&context, response)
+
+	response.Headers = cloneStringMap(response.Headers)
+	if response.Headers == nil {
+		response.Headers = map[string]string{}
+	}
+	headerSet(response.Headers, HEADER_LOG_ID, context.logger.Id)
+
+	finish := func() {
+		if captured {
+			context.logger.RevertNativeLogs()
+			nativeCapture.Unlock()
+		}
+		context.logger.End()
+		cancel()
+	}
+
+	return response, &context.logger, finish
+}
+
+func (s Server) run(context *Context) (response Response) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			context.logger.writePanic(recovered, debug.Stack(), "")
+			runPanicHooks(context, recovered)
+			response = context.Res.Text("", context.Res.WithStatusCode(500))
+		}
+	}()
+
+	if s.Handler == nil {
+		context.Error("Handler is not defined.")
+		return context.Res.Text("", context.Res.WithStatusCode(500))
+	}
+
+	runStartHooks(context)
+
+	return s.Handler(context)
+}
+
+func (s Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	req, err := NewRequestFromHTTP(r)
+	if err != nil {
+		w.WriteHeader(500)
+		return
+	}
+
+	response, _, finish := s.invoke(r.Context(), req)
+	defer finish()
+
+	for key, values := range response.AllHeaders() {
+		for _, value := range values {
+			w.Header().Add(key, value)
+		}
+	}
+
+	for key := range response.Trailers {
+		w.Header().Add("trailer", key)
+	}
+
+	statusCode := response.StatusCode
+	if statusCode == 0 {
+		statusCode = 200
+	}
+
+	w.WriteHeader(statusCode)
+
+	if response.IsStream() {
+		writeStream(w, response.BodyReader, s.ShutdownGracePeriod)
+	} else {
+		w.Write(response.Body)
+	}
+
+	for key, value := range response.Trailers {
+		w.Header().Set(key, value)
+	}
+}
+
+func NewRequestFromHTTP(r *http.Request) (ContextRequest, error) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return ContextRequest{}, err
+	}
+
+	req := ContextRequest{
+		Method: r.Method,
+		Url:    r.URL.RequestURI(),
+		Path:   r.URL.Path,
+	}
+	req.SetBodyBinary(body)
+
+	req.Headers = map[string]string{}
+	req.SetHeaderValues(r.Header)
+
+	req.Scheme = "http"
+	if proto := req.Header("x-forwarded-proto"); proto != "" && fromTrustedProxy(r.RemoteAddr) {
+		proto, _, _ = strings.Cut(proto, ",")
+		req.Scheme = strings.ToLower(strings.TrimSpace(proto))
+	} else if r.TLS != nil {
+		req.Scheme = "https"
+	}
+
+	if err := req.SetHost(r.Host); err != nil {
+		req.Host = r.Host
+		req.Port = DefaultPort(req.Scheme)
+	}
+
+	req.SetQueryString(r.URL.RawQuery)
+
+	return req, nil
+}
+
+// fromTrustedProxy reports whether the peer is a proxy under
+// DefaultProxyPolicy, whose x-forwarded-proto can be believed.
+func fromTrustedProxy(remoteAddr string) bool {
+	ip := parseForwardedIP(remoteAddr)
+	return ip != nil && DefaultProxyPolicy.IsTrusted(ip)
+}
+
+// ListenAndServe stops accepting executions on SIGTERM and gives in-flight
+// ones, including streams, ShutdownGracePeriod to finish, then as long again
+// for background OTLP exports.
+func (s Server) ListenAndServe(port int) error {
+	httpServer := &http.Server{
+		Addr:    ":" + strconv.Itoa(port),
+		Handler: s,
+	}
+
+	HandleShutdownSignals()
+
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		<-ShuttingDown()
+
+		ctx, cancel := context.WithTimeout(context.Background(), s.ShutdownGracePeriod)
+		defer cancel()
+		httpServer.Shutdown(ctx)
+	}()
+
+	err := httpServer.ListenAndServe()
+	if errors.Is(err, http.ErrServerClosed) {
+		<-stopped
+		waitOtlpExports(s.ShutdownGracePeriod)
+		closeSyslogConnections()
+		return nil
+	}
+
+	return err
+}
@@ -0,0 +1,210 @@
+package openruntimes
+
+import (
+	"io"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// streamContextHandler streams "LIVE" only if the execution context is still
+// live once the body starts being read, and fails the stream otherwise.
+func streamContextHandler(c *Context) Response {
+	reader, writer := io.Pipe()
+
+	go func() {
+		if _, err := writer.Write([]byte{}); err != nil {
+			return
+		}
+		if err := c.Context().Err(); err != nil {
+			writer.CloseWithError(err)
+			return
+		}
+		writer.Write([]byte("LIVE"))
+		writer.Close()
+	}()
+
+	return c.Res.Stream(reader)
+}
+
+func TestServeHTTPKeepsContextForStream(t *testing.T) {
+	server := NewServer(streamContextHandler)
+
+	recorder := httptest.NewRecorder()
+	server.ServeHTTP(recorder, httptest.NewRequest("GET", "/", nil))
+
+	if body := recorder.Body.String(); body != "LIVE" {
+		t.Fatalf("body = %q, want %q", body, "LIVE")
+	}
+}
+
+func TestInvokeKeepsContextUntilStreamIsRead(t *testing.T) {
+	server := NewServer(streamContextHandler)
+
+	response, _ := server.Invoke(ContextRequest{Method: "GET", Path: "/", Url: "/"})
+
+	body, err := response.ReadAll()
+	if err != nil {
+		t.Fatalf("ReadAll() error = %v", err)
+	}
+	if string(body) != "LIVE" {
+		t.Fatalf("body = %q, want %q", body, "LIVE")
+	}
+}
+
+func TestInvokeCancelsContextForBufferedResponse(t *testing.T) {
+	var captured *Context
+	server := NewServer(func(c *Context) Response {
+		captured = c
+		return c.Res.Text("ok")
+	})
+
+	server.Invoke(ContextRequest{Method: "GET", Path: "/", Url: "/"})
+
+	if captured.Context().Err() == nil {
+		t.Fatal("context is still live after Invoke returned")
+	}
+}
+
+func TestServeHTTPKeepsLoggerOpenForStream(t *testing.T) {
+	dir := t.TempDir()
+
+	server := NewServer(func(c *Context) Response {
+		reader, writer := io.Pipe()
+
+		go func() {
+			if _, err := writer.Write([]byte{}); err != nil {
+				return
+			}
+			c.Log("written during stream")
+			writer.Write([]byte("done"))
+			writer.Close()
+		}()
+
+		return c.Res.Stream(reader)
+	})
+	server.LoggerOptions = LoggerOptions{Dir: dir}
+
+	request := httptest.NewRequest("GET", "/", nil)
+	request.Header.Set(HEADER_LOG_ID, "stream")
+
+	recorder := httptest.NewRecorder()
+	server.ServeHTTP(recorder, request)
+
+	logs, err := os.ReadFile(filepath.Join(dir, "stream_logs.log"))
+	if err != nil {
+		t.Fatalf("reading logs: %v", err)
+	}
+	if !strings.Contains(string(logs), "written during stream") {
+		t.Fatalf("logs = %q, want the message written during the stream", logs)
+	}
+}
+
+func TestServeHTTPStripsExecutorHeaders(t *testing.T) {
+	var header string
+	var values []string
+	server := NewServer(func(c *Context) Response {
+		header = c.Req.Header("x-open-runtimes-secret")
+		values = c.Req.HeaderValues("x-open-runtimes-secret")
+		return c.Res.Text("ok")
+	})
+
+	request := httptest.NewRequest("GET", "/", nil)
+	request.Header.Set("x-open-runtimes-secret", "KEY")
+	request.Header.Set(HEADER_LOGGING, "disabled")
+
+	server.ServeHTTP(httptest.NewRecorder(), request)
+
+	if header != "" || len(values) != 0 {
+		t.Fatalf("handler saw executor header: Header() = %q, HeaderValues() = %q", header, values)
+	}
+}
+
+func TestServeHTTPLeavesSharedResponseHeaders(t *testing.T) {
+	shared := Response{Body: []byte("ok"), StatusCode: 200, Headers: map[string]string{"content-type": "text/plain"}}
+	server := NewServer(func(c *Context) Response {
+		return shared
+	})
+
+	var wait sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wait.Add(1)
+		go func() {
+			defer wait.Done()
+
+			request := httptest.NewRequest("GET", "/", nil)
+			request.Header.Set(HEADER_LOGGING, "disabled")
+			server.ServeHTTP(httptest.NewRecorder(), request)
+		}()
+	}
+	wait.Wait()
+
+	if _, ok := headerGet(shared.Headers, HEADER_LOG_ID); ok {
+		t.Fatalf("handler's headers = %v, want them left untouched", shared.Headers)
+	}
+}
+
+func TestServeHTTPCapturesNativeLogsOnce(t *testing.T) {
+	dir := t.TempDir()
+	started := make(chan struct{})
+	release := make(chan struct{})
+
+	server := NewServer(func(c *Context) Response {
+		if c.Req.Path == "/first" {
+			close(started)
+			<-release
+		}
+		return c.Res.Text("ok")
+	})
+	server.LoggerOptions = LoggerOptions{Dir: dir}
+
+	stdout := os.Stdout
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		server.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/first", nil))
+	}()
+	<-started
+
+	captured := os.Stdout
+	server.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/second", nil))
+	if os.Stdout != captured {
+		t.Error("concurrent execution swapped os.Stdout again")
+	}
+
+	close(release)
+	<-done
+	if os.Stdout != stdout {
+		t.Fatal("os.Stdout was not restored")
+	}
+}
+
+func TestNewRequestFromHTTPScheme(t *testing.T) {
+	tests := []struct {
+		name   string
+		remote string
+		want   string
+	}{
+		{name: "trusted proxy", remote: "10.0.0.1:1234", want: "https"},
+		{name: "untrusted client", remote: "9.9.9.9:1234", want: "http"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			request := httptest.NewRequest("GET", "/", nil)
+			request.RemoteAddr = test.remote
+			request.Header.Set("x-forwarded-proto", "HTTPS, http")
+
+			req, err := NewRequestFromHTTP(request)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if req.Scheme != test.want {
+				t.Errorf("Scheme = %q, want %q", req.Scheme, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package openruntimes
+
+import (
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+)
+
+const SHUTDOWN_GRACE_PERIOD = 10 * time.Second
+
+var (
+	shutdownOnce    sync.Once
+	shutdownChannel = make(chan struct{})
+	signalsOnce     sync.Once
+)
+
+// Shutdown notifies every active Context through Done. It is safe to call more than once.
+func Shutdown() {
+	shutdownOnce.Do(func() {
+		close(shutdownChannel)
+	})
+}
+
+func ShuttingDown() <-chan struct{} {
+	return shutdownChannel
+}
+
+func IsShuttingDown() bool {
+	select {
+	case <-shutdownChannel:
+		return true
+	default:
+		return false
+	}
+}
+
+// HandleShutdownSignals turns SIGTERM and SIGINT from the executor into Shutdown.
+func HandleShutdownSignals() {
+	signalsOnce.Do(func() {
+		signals := make(chan os.Signal, 1)
+		signal.Notify(signals, syscall.SIGTERM, os.Interrupt)
+
+		go func() {
+			<-signals
+			Shutdown()
+		}()
+	})
+}
+
+func (c *Context) Done() <-chan struct{} {
+	return ShuttingDown()
+}
@@ -0,0 +1,87 @@
+package openruntimes
+
+import (
+	"errors"
+	"io/fs"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/open-runtimes/types-for-go/v4/mimetypes"
+)
+
+// StaticFS serves req.Path from the root directory of fsys, usually an
+// embed.FS. Directories serve their index.html, and paths without an
+// extension that match nothing fall back to root/index.html so client-side
+// routes of a single-page app work. Responses carry an ETag and honour
+// If-None-Match.
+func (r ContextResponse) StaticFS(fsys fs.FS, root string, req ContextRequest, optionalSetters ...ResponseOption) Response {
+	method := strings.ToUpper(req.Method)
+	if method != "GET" && method != "HEAD" {
+		return r.MethodNotAllowed("GET, HEAD")
+	}
+
+	root = strings.Trim(path.Clean("/"+root), "/")
+	if root == "" {
+		root = "."
+	}
+
+	name := strings.TrimPrefix(path.Clean("/"+req.Path), "/")
+
+	content, filename, err := readStatic(fsys, path.Join(root, name))
+	if errors.Is(err, fs.ErrNotExist) && path.Ext(name) == "" {
+		content, filename, err = readStatic(fsys, path.Join(root, "index.html"))
+	}
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return r.NotFound()
+		}
+		return r.InternalError(err)
+	}
+
+	etag := ComputeETag(content)
+	headers := map[string]string{
+		"content-type": mimetypes.TypeByFilename(filename),
+		"etag":         etag,
+	}
+	if path.Base(filename) == "index.html" {
+		headers["cache-control"] = "no-cache"
+	}
+
+	if req.IsNotModified(etag, time.Time{}) {
+		return r.NotModified(r.WithHeaders(map[string]string{"etag": etag}))
+	}
+
+	headers["content-length"] = strconv.Itoa(len(content))
+	optionalSetters = append([]ResponseOption{r.WithHeaders(headers)}, optionalSetters...)
+
+	if method == "HEAD" {
+		return r.Binary([]byte{}, optionalSetters...)
+	}
+	return r.Binary(content, optionalSetters...)
+}
+
+// readStatic reads name, or name/index.html when name is a directory.
+func readStatic(fsys fs.FS, name string) ([]byte, string, error) {
+	info, err := fs.Stat(fsys, name)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if info.IsDir() {
+		name = path.Join(name, "index.html")
+	}
+
+	content, err := fs.ReadFile(fsys, name)
+	return content, name, err
+}
+
+// Static mounts StaticFS under prefix, serving root of fsys for every path below it.
+func (r *Router) Static(prefix string, fsys fs.FS, root string) {
+	r.Any(strings.TrimRight(prefix, "/")+"/*filepath", func(c *Context) Response {
+		req := c.Req
+		req.Path = "/" + req.Param("filepath")
+		return c.Res.StaticFS(fsys, root, req)
+	})
+}
@@ -0,0 +1,44 @@
+package openruntimes
+
+import (
+	"bytes"
+	"io"
+)
+
+// LoggerStats describes what a Logger has written so far. Lines counts
+// newline-terminated lines across all streams.
+type LoggerStats struct {
+	Lines       int64 `json:"lines"`
+	Bytes       int64 `json:"bytes"`
+	WriteErrors int64 `json:"writeErrors"`
+	Truncated   int64 `json:"truncated"`
+	Suppressed  int64 `json:"suppressed"`
+}
+
+func (l *Logger) Stats() LoggerStats {
+	if l.counters == nil {
+		return LoggerStats{}
+	}
+
+	return LoggerStats{
+		Lines:       l.counters.lines.Load(),
+		Bytes:       l.counters.bytes.Load(),
+		WriteErrors: l.counters.writeErrors.Load(),
+		Truncated:   l.counters.truncated.Load(),
+		Suppressed:  l.counters.suppressed.Load(),
+	}
+}
+
+func (l *Logger) emit(stream io.Writer, data []byte) {
+	written, err := stream.Write(data)
+
+	if l.counters == nil {
+		return
+	}
+
+	l.counters.bytes.Add(int64(written))
+	l.counters.lines.Add(int64(bytes.Count(data[:written], []byte{'\n'})))
+	if err != nil {
+		l.counters.writeErrors.Add(1)
+	}
+}
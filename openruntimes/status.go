@@ -0,0 +1,91 @@
+package openruntimes
+
+import (
+	"math"
+	"strconv"
+	"time"
+)
+
+func errorBody(message string) map[string]interface{} {
+	return map[string]interface{}{
+		"errors": []map[string]string{{"message": message}},
+	}
+}
+
+func (r ContextResponse) errorResponse(statusCode int, message string, optionalSetters []ResponseOption) Response {
+	optionalSetters = append([]ResponseOption{r.WithStatusCode(statusCode)}, optionalSetters...)
+	return r.Json(errorBody(message), optionalSetters...)
+}
+
+func (r ContextResponse) BadRequest(message string, optionalSetters ...ResponseOption) Response {
+	if message == "" {
+		message = "Bad Request"
+	}
+	return r.errorResponse(400, message, optionalSetters)
+}
+
+func (r ContextResponse) Unauthorized(optionalSetters ...ResponseOption) Response {
+	return r.errorResponse(401, "Unauthorized", optionalSetters)
+}
+
+func (r ContextResponse) Forbidden(optionalSetters ...ResponseOption) Response {
+	return r.errorResponse(403, "Forbidden", optionalSetters)
+}
+
+func (r ContextResponse) NotFound(optionalSetters ...ResponseOption) Response {
+	return r.errorResponse(404, "Not Found", optionalSetters)
+}
+
+// MethodNotAllowed lists the methods the resource does support in Allow.
+func (r ContextResponse) MethodNotAllowed(allow string, optionalSetters ...ResponseOption) Response {
+	optionalSetters = append([]ResponseOption{r.WithHeaders(map[string]string{"allow": allow})}, optionalSetters...)
+	return r.errorResponse(405, "Method Not Allowed", optionalSetters)
+}
+
+func (r ContextResponse) Conflict(optionalSetters ...ResponseOption) Response {
+	return r.errorResponse(409, "Conflict", optionalSetters)
+}
+
+func (r ContextResponse) TooManyRequests(retryAfter time.Duration, optionalSetters ...ResponseOption) Response {
+	if retryAfter > 0 {
+		seconds := int(math.Ceil(retryAfter.Seconds()))
+		optionalSetters = append([]ResponseOption{r.WithHeaders(map[string]string{
+			"retry-after": strconv.Itoa(seconds),
+		})}, optionalSetters...)
+	}
+	return r.errorResponse(429, "Too Many Requests", optionalSetters)
+}
+
+// InternalError never exposes err to the client; log it with Context.Error.
+func (r ContextResponse) InternalError(err error, optionalSetters ...ResponseOption) Response {
+	return r.errorResponse(500, "Internal Server Error", optionalSetters)
+}
+
+// Created answers the REST create pattern: 201 with a Location header and,
+// when body is not nil, a JSON representation of the new resource.
+func (r ContextResponse) Created(location string, body any, optionalSetters ...ResponseOption) Response {
+	optionalSetters = append([]ResponseOption{r.WithStatusCode(201)}, optionalSetters...)
+	if location != "" {
+		optionalSetters = append(optionalSetters, r.WithHeaders(map[string]string{"location": location}))
+	}
+
+	if body == nil {
+		return r.Binary([]byte{}, optionalSetters...)
+	}
+	return r.Json(body, optionalSetters...)
+}
+
+func (r ContextResponse) Accepted(body any, optionalSetters ...ResponseOption) Response {
+	optionalSetters = append([]ResponseOption{r.WithStatusCode(202)}, optionalSetters...)
+
+	if body == nil {
+		return r.Binary([]byte{}, optionalSetters...)
+	}
+	return r.Json(body, optionalSetters...)
+}
+
+// NoContent behaves like Empty but accepts options, such as headers.
+func (r ContextResponse) NoContent(optionalSetters ...ResponseOption) Response {
+	optionalSetters = append(optionalSetters, r.WithStatusCode(204))
+	return r.Binary([]byte{}, optionalSetters...)
+}
@@ -0,0 +1,127 @@
+package openruntimes
+
+import (
+	"io"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const STREAM_CHUNK_SIZE = 32 * 1024
+
+// Stream returns a response whose body is read from reader by the runtime
+// server in chunks. The reader is closed after sending when it is an io.Closer.
+// WithETag is ignored, as the body is not known up front.
+func (r ContextResponse) Stream(reader io.Reader, optionalSetters ...ResponseOption) Response {
+	response := r.binary(nil, true, optionalSetters...)
+	response.BodyReader = reader
+
+	options := Response{}.New()
+	for _, opt := range optionalSetters {
+		opt(options)
+	}
+
+	if _, ok := lookupEncoder(options.compression); ok {
+		response.BodyReader = compressReader(options.compression, reader)
+		response.Headers = compressedHeaders(response.Headers, options.compression, -1)
+	}
+
+	return response
+}
+
+func (r Response) IsStream() bool {
+	return r.BodyReader != nil
+}
+
+// ReadAll buffers a streamed body, for transports that cannot send chunks.
+func (r Response) ReadAll() ([]byte, error) {
+	if r.BodyReader == nil {
+		return r.Body, nil
+	}
+
+	if closer, ok := r.BodyReader.(io.Closer); ok {
+		defer closer.Close()
+	}
+
+	return io.ReadAll(r.BodyReader)
+}
+
+// writeStream keeps sending after Shutdown for at most grace, then closes the
+// reader so a blocked Read returns and the final chunks are not left hanging.
+func writeStream(w http.ResponseWriter, reader io.Reader, grace time.Duration) error {
+	finished := make(chan struct{})
+	defer close(finished)
+
+	closer, _ := reader.(io.Closer)
+	if closer != nil {
+		defer closer.Close()
+	}
+
+	var deadline atomic.Bool
+	go func() {
+		select {
+		case <-finished:
+			return
+		case <-ShuttingDown():
+		}
+
+		select {
+		case <-finished:
+		case <-time.After(grace):
+			deadline.Store(true)
+			if closer != nil {
+				closer.Close()
+			}
+		}
+	}()
+
+	flusher, _ := w.(http.Flusher)
+	buffer := make([]byte, STREAM_CHUNK_SIZE)
+
+	for !deadline.Load() {
+		n, err := reader.Read(buffer)
+		if n > 0 {
+			if _, writeErr := w.Write(buffer[:n]); writeErr != nil {
+				return writeErr
+			}
+			if flusher != nil {
+				flusher.Flush()
+			}
+		}
+
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// finishingReader ends the execution behind a stream once the body has been
+// read to the end, has failed, or is closed, whichever comes first.
+type finishingReader struct {
+	reader io.Reader
+	finish func()
+	once   sync.Once
+}
+
+func (f *finishingReader) Read(p []byte) (int, error) {
+	n, err := f.reader.Read(p)
+	if err != nil {
+		f.once.Do(f.finish)
+	}
+	return n, err
+}
+
+func (f *finishingReader) Close() error {
+	var err error
+	if closer, ok := f.reader.(io.Closer); ok {
+		err = closer.Close()
+	}
+	f.once.Do(f.finish)
+	return err
+}
@@ -0,0 +1,20 @@
+package openruntimes
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestStreamSkipsETag(t *testing.T) {
+	res := ContextResponse{}
+
+	streamed := res.Stream(strings.NewReader("body"), res.WithETag())
+	if etag, ok := headerGet(streamed.Headers, "etag"); ok {
+		t.Errorf("Stream() etag = %q, want none", etag)
+	}
+
+	buffered := res.Text("body", res.WithETag())
+	if etag, _ := headerGet(buffered.Headers, "etag"); etag != ComputeETag([]byte("body")) {
+		t.Errorf("Text() etag = %q, want the hash of the body", etag)
+	}
+}
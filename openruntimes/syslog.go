@@ -0,0 +1,209 @@
+package openruntimes
+
+import (
+	"bytes"
+	"errors"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const SYSLOG_FACILITY_LOCAL0 = 16
+
+const (
+	SYSLOG_SEVERITY_ERROR  = 3
+	SYSLOG_SEVERITY_NOTICE = 5
+	SYSLOG_SEVERITY_INFO   = 6
+)
+
+// syslogWriter forwards complete lines as RFC 5424 messages over the
+// process's shared connection to the syslog address.
+type syslogWriter struct {
+	mutex    sync.Mutex
+	conn     *syslogConnection
+	severity int
+	hostname string
+	msgId    string
+	pending  bytes.Buffer
+}
+
+// syslogConnection is the one connection a process keeps to a syslog address,
+// shared by every Logger's writers. Streams use octet counting framing
+// (RFC 6587); datagrams carry one message each. A failed write drops the
+// connection and the next write dials again.
+type syslogConnection struct {
+	mutex   sync.Mutex
+	address string
+	conn    net.Conn
+	stream  bool
+}
+
+var (
+	syslogMutex       sync.Mutex
+	syslogConnections = map[string]*syslogConnection{}
+)
+
+func (o LoggerOptions) syslogAddress() string {
+	if o.Syslog != "" {
+		return o.Syslog
+	}
+
+	return os.Getenv("OPEN_RUNTIMES_LOGS_SYSLOG")
+}
+
+// dialSyslog accepts udp://host:port, tcp://host:port and unix:///path.
+func dialSyslog(address string) (net.Conn, bool, error) {
+	parsed, err := url.Parse(address)
+	if err != nil {
+		return nil, false, errors.New("invalid syslog address")
+	}
+
+	switch parsed.Scheme {
+	case "udp":
+		conn, err := net.DialTimeout("udp", parsed.Host, 5*time.Second)
+		return conn, false, err
+	case "tcp":
+		conn, err := net.DialTimeout("tcp", parsed.Host, 5*time.Second)
+		return conn, true, err
+	case "unix":
+		if conn, err := net.Dial("unixgram", parsed.Path); err == nil {
+			return conn, false, nil
+		}
+		conn, err := net.Dial("unix", parsed.Path)
+		return conn, true, err
+	}
+
+	return nil, false, errors.New("unsupported syslog network: " + parsed.Scheme)
+}
+
+// sharedSyslog returns the process's connection to address, dialling it on
+// first use.
+func sharedSyslog(address string) (*syslogConnection, error) {
+	syslogMutex.Lock()
+	defer syslogMutex.Unlock()
+
+	if connection, ok := syslogConnections[address]; ok {
+		return connection, nil
+	}
+
+	conn, stream, err := dialSyslog(address)
+	if err != nil {
+		return nil, err
+	}
+
+	connection := &syslogConnection{address: address, conn: conn, stream: stream}
+	syslogConnections[address] = connection
+	return connection, nil
+}
+
+// closeSyslogConnections closes every shared connection, once, at shutdown.
+func closeSyslogConnections() {
+	syslogMutex.Lock()
+	defer syslogMutex.Unlock()
+
+	for address, connection := range syslogConnections {
+		connection.mutex.Lock()
+		if connection.conn != nil {
+			connection.conn.Close()
+			connection.conn = nil
+		}
+		connection.mutex.Unlock()
+
+		delete(syslogConnections, address)
+	}
+}
+
+func (c *syslogConnection) write(message string) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.conn == nil {
+		conn, stream, err := dialSyslog(c.address)
+		if err != nil {
+			return err
+		}
+		c.conn, c.stream = conn, stream
+	}
+
+	if c.stream {
+		message = strconv.Itoa(len(message)) + " " + message
+	}
+
+	if _, err := c.conn.Write([]byte(message)); err != nil {
+		c.conn.Close()
+		c.conn = nil
+		return err
+	}
+
+	return nil
+}
+
+func newSyslogWriter(conn *syslogConnection, severity int, msgId string) *syslogWriter {
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		hostname = "-"
+	}
+
+	return &syslogWriter{
+		conn:     conn,
+		severity: severity,
+		hostname: hostname,
+		msgId:    msgId,
+	}
+}
+
+func (w *syslogWriter) Write(p []byte) (int, error) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	w.pending.Write(p)
+
+	for {
+		line, err := w.pending.ReadBytes('\n')
+		if err != nil {
+			// Keep the partial line until its newline arrives.
+			rest := append([]byte{}, line...)
+			w.pending.Reset()
+			w.pending.Write(rest)
+			break
+		}
+
+		if err := w.send(bytes.TrimRight(line, "\n")); err != nil {
+			return len(p), err
+		}
+	}
+
+	return len(p), nil
+}
+
+func (w *syslogWriter) send(line []byte) error {
+	if len(line) == 0 {
+		return nil
+	}
+
+	message := "<" + strconv.Itoa(SYSLOG_FACILITY_LOCAL0*8+w.severity) + ">1 " +
+		time.Now().UTC().Format(time.RFC3339Nano) + " " +
+		w.hostname + " open-runtimes " +
+		strconv.Itoa(os.Getpid()) + " " +
+		w.msgId + " - " + string(line)
+
+	return w.conn.write(message)
+}
+
+// Close sends a trailing partial line. The shared connection stays open for
+// the next execution.
+func (w *syslogWriter) Close() error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if w.pending.Len() == 0 {
+		return nil
+	}
+
+	err := w.send(w.pending.Bytes())
+	w.pending.Reset()
+	return err
+}
@@ -0,0 +1,76 @@
+package openruntimes
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSyslogSharesOneConnection(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	var accepted atomic.Int32
+	messages := make(chan string, 16)
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			accepted.Add(1)
+
+			go func() {
+				reader := bufio.NewReader(conn)
+				for {
+					length, err := reader.ReadString(' ')
+					if err != nil {
+						return
+					}
+					size, _ := strconv.Atoi(strings.TrimSpace(length))
+					frame := make([]byte, size)
+					if _, err := io.ReadFull(reader, frame); err != nil {
+						return
+					}
+					messages <- string(frame)
+				}
+			}()
+		}
+	}()
+
+	address := "tcp://" + listener.Addr().String()
+	defer closeSyslogConnections()
+
+	for _, id := range []string{"first", "second"} {
+		logger, err := NewLoggerWithOptions("enabled", id, LoggerOptions{Syslog: address})
+		if err != nil {
+			t.Fatalf("NewLoggerWithOptions() error = %v", err)
+		}
+		logger.Info("hello from " + id)
+		logger.End()
+	}
+
+	for _, id := range []string{"first", "second"} {
+		select {
+		case message := <-messages:
+			if !strings.Contains(message, " "+id+" - hello from "+id) {
+				t.Errorf("message = %q, want the %s logger's line", message, id)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatalf("no message from the %s logger", id)
+		}
+	}
+
+	if count := accepted.Load(); count != 1 {
+		t.Errorf("accepted %d connections, want 1 shared by both loggers", count)
+	}
+}
@@ -0,0 +1,86 @@
+package openruntimes
+
+import (
+	"bytes"
+	"errors"
+	"html/template"
+	"io/fs"
+	"sync"
+)
+
+var (
+	templatesMutex sync.RWMutex
+	templates      = template.New("")
+	templateFuncs  = template.FuncMap{}
+)
+
+// RegisterTemplateFuncs must be called before the templates that use them are registered.
+func RegisterTemplateFuncs(funcs template.FuncMap) {
+	templatesMutex.Lock()
+	defer templatesMutex.Unlock()
+
+	for name, fn := range funcs {
+		templateFuncs[name] = fn
+	}
+	templates.Funcs(templateFuncs)
+}
+
+// RegisterTemplates parses templates matching patterns from fsys, typically an
+// embed.FS. Each file is addressable by its base name and may include the others.
+func RegisterTemplates(fsys fs.FS, patterns ...string) error {
+	templatesMutex.Lock()
+	defer templatesMutex.Unlock()
+
+	parsed, err := templates.Clone()
+	if err != nil {
+		return err
+	}
+
+	parsed, err = parsed.ParseFS(fsys, patterns...)
+	if err != nil {
+		return errors.New("could not parse templates: " + err.Error())
+	}
+
+	templates = parsed
+	return nil
+}
+
+func RegisterTemplate(name string, text string) error {
+	templatesMutex.Lock()
+	defer templatesMutex.Unlock()
+
+	parsed, err := templates.Clone()
+	if err != nil {
+		return err
+	}
+
+	if _, err := parsed.New(name).Parse(text); err != nil {
+		return errors.New("could not parse template " + name + ": " + err.Error())
+	}
+
+	templates = parsed
+	return nil
+}
+
+func (r ContextResponse) Render(name string, data any, optionalSetters ...ResponseOption) Response {
+	templatesMutex.RLock()
+	tmpl := templates.Lookup(name)
+	templatesMutex.RUnlock()
+
+	if tmpl == nil {
+		optionalSetters = append(optionalSetters, r.WithStatusCode(500))
+		return r.Text("Template "+name+" is not registered.", optionalSetters...)
+	}
+
+	var body bytes.Buffer
+	if err := tmpl.Execute(&body, data); err != nil {
+		optionalSetters = append(optionalSetters, r.WithStatusCode(500))
+		return r.Text("Error rendering template.", optionalSetters...)
+	}
+
+	optionalSetters = append(optionalSetters, r.WithHeaders(map[string]string{
+		"content-type": "text/html; charset=utf-8",
+	}))
+
+	return r.Binary(body.Bytes(), optionalSetters...)
+}
@@ -0,0 +1,68 @@
+package openruntimes
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"strings"
+)
+
+const HEADER_REQUEST_ID = "x-request-id"
+const HEADER_TRACEPARENT = "traceparent"
+
+func randomHex(size int) string {
+	bytes := make([]byte, size)
+	rand.Read(bytes)
+	return hex.EncodeToString(bytes)
+}
+
+// parseTraceparent returns the trace id of a W3C traceparent header
+// ("00-<trace id>-<parent id>-<flags>"), or "" when it is malformed.
+func parseTraceparent(header string) string {
+	parts := strings.Split(strings.TrimSpace(header), "-")
+	if len(parts) < 4 || len(parts[0]) != 2 || len(parts[1]) != 32 || len(parts[2]) != 16 {
+		return ""
+	}
+
+	traceId := strings.ToLower(parts[1])
+	if _, err := hex.DecodeString(traceId); err != nil || traceId == strings.Repeat("0", 32) {
+		return ""
+	}
+
+	return traceId
+}
+
+// bindTrace takes the trace and request ids from the request headers,
+// generating those that are missing, and hands them to the logger.
+func (c *Context) bindTrace() {
+	if c.logger.traceId != "" {
+		return
+	}
+
+	c.logger.traceId = parseTraceparent(c.Req.Header(HEADER_TRACEPARENT))
+	if c.logger.traceId == "" {
+		c.logger.traceId = randomHex(16)
+	}
+
+	c.logger.requestId = strings.TrimSpace(c.Req.Header(HEADER_REQUEST_ID))
+	if c.logger.requestId == "" {
+		c.logger.requestId = randomHex(16)
+	}
+}
+
+// TraceID is the W3C trace id from traceparent, or a generated one. It is
+// part of every JSON and OTLP log record.
+func (c *Context) TraceID() string {
+	c.bindTrace()
+	return c.logger.traceId
+}
+
+// RequestID is the x-request-id header, or a generated id.
+func (c *Context) RequestID() string {
+	c.bindTrace()
+	return c.logger.requestId
+}
+
+// WithRequestID echoes RequestID in the x-request-id response header.
+func (c *Context) WithRequestID() ResponseOption {
+	return c.Res.WithHeaders(map[string]string{HEADER_REQUEST_ID: c.RequestID()})
+}
@@ -0,0 +1,139 @@
+package openruntimes
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/open-runtimes/types-for-go/v4/mimetypes"
+)
+
+// TransportRequest and TransportResponse are the JSON envelopes used by
+// platforms that cannot carry raw bytes, such as Lambda-style adapters.
+type TransportRequest struct {
+	Method          string            `json:"method"`
+	Scheme          string            `json:"scheme"`
+	Host            string            `json:"host"`
+	Path            string            `json:"path"`
+	QueryString     string            `json:"queryString"`
+	Headers         map[string]string `json:"headers"`
+	Body            string            `json:"body"`
+	IsBase64Encoded bool              `json:"isBase64Encoded"`
+}
+
+type TransportResponse struct {
+	StatusCode        int                 `json:"statusCode"`
+	Headers           map[string]string   `json:"headers"`
+	MultiValueHeaders map[string][]string `json:"multiValueHeaders,omitempty"`
+	Cookies           []string            `json:"cookies,omitempty"`
+	Body              string              `json:"body"`
+	IsBase64Encoded   bool                `json:"isBase64Encoded"`
+}
+
+func (t TransportRequest) ContextRequest() (ContextRequest, error) {
+	body := []byte(t.Body)
+
+	if t.IsBase64Encoded {
+		decoded, err := base64.StdEncoding.DecodeString(t.Body)
+		if err != nil {
+			return ContextRequest{}, errors.New("could not decode base64 body")
+		}
+		body = decoded
+	}
+
+	req := ContextRequest{
+		Method: strings.ToUpper(t.Method),
+		Scheme: t.Scheme,
+		Path:   t.Path,
+	}
+	if req.Scheme == "" {
+		req.Scheme = "https"
+	}
+	if req.Path == "" {
+		req.Path = "/"
+	}
+
+	req.Headers = map[string]string{}
+	for key, value := range t.Headers {
+		req.SetHeader(key, value)
+	}
+
+	if err := req.SetHost(t.Host); err != nil {
+		req.Host = t.Host
+	}
+
+	req.SetQueryString(t.QueryString)
+	req.SetBodyBinary(body)
+
+	req.Url = req.Path
+	if req.QueryString != "" {
+		req.Url += "?" + req.QueryString
+	}
+
+	return req, nil
+}
+
+func NewTransportResponse(response Response) TransportResponse {
+	statusCode := response.StatusCode
+	if statusCode == 0 {
+		statusCode = 200
+	}
+
+	transport := TransportResponse{
+		StatusCode: statusCode,
+		Headers:    response.Headers,
+		Cookies:    response.SetCookieHeaders(),
+	}
+
+	if len(response.HeaderValues) > 0 {
+		transport.MultiValueHeaders = map[string][]string{}
+		for key, values := range response.AllHeaders() {
+			if !strings.EqualFold(key, "set-cookie") {
+				transport.MultiValueHeaders[key] = values
+			}
+		}
+	}
+
+	contentType := ""
+	for key, value := range response.Headers {
+		if strings.EqualFold(key, "content-type") {
+			contentType = value
+		}
+	}
+
+	if !utf8.Valid(response.Body) || (contentType != "" && mimetypes.IsBinary(contentType)) {
+		transport.Body = base64.StdEncoding.EncodeToString(response.Body)
+		transport.IsBase64Encoded = true
+	} else {
+		transport.Body = string(response.Body)
+	}
+
+	return transport
+}
+
+func (s Server) InvokeTransport(payload []byte) ([]byte, error) {
+	var transport TransportRequest
+	if err := json.Unmarshal(payload, &transport); err != nil {
+		return nil, errors.New("could not parse transport request")
+	}
+
+	req, err := transport.ContextRequest()
+	if err != nil {
+		return nil, err
+	}
+
+	response, _ := s.Invoke(req)
+
+	if response.IsStream() {
+		body, err := response.ReadAll()
+		if err != nil {
+			return nil, errors.New("could not read streamed body")
+		}
+		response.Body = body
+		response.BodyReader = nil
+	}
+
+	return json.Marshal(NewTransportResponse(response))
+}
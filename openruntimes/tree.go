@@ -0,0 +1,80 @@
+package openruntimes
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+)
+
+const (
+	treeScalar = iota
+	treeMap
+	treeList
+)
+
+// treeNode is an order-preserving view of a JSON document. Encoders for other
+// formats build on it so they honor json tags, omitempty and MarshalJSON.
+type treeNode struct {
+	kind   int
+	value  interface{}
+	keys   []string
+	values []treeNode
+}
+
+func newTree(v any) (treeNode, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return treeNode{}, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	return readTree(decoder)
+}
+
+func readTree(decoder *json.Decoder) (treeNode, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return treeNode{}, err
+	}
+
+	delim, ok := token.(json.Delim)
+	if !ok {
+		return treeNode{kind: treeScalar, value: token}, nil
+	}
+
+	switch delim {
+	case '{':
+		node := treeNode{kind: treeMap}
+		for decoder.More() {
+			keyToken, err := decoder.Token()
+			if err != nil {
+				return treeNode{}, err
+			}
+
+			value, err := readTree(decoder)
+			if err != nil {
+				return treeNode{}, err
+			}
+
+			node.keys = append(node.keys, keyToken.(string))
+			node.values = append(node.values, value)
+		}
+		_, err := decoder.Token()
+		return node, err
+	case '[':
+		node := treeNode{kind: treeList}
+		for decoder.More() {
+			value, err := readTree(decoder)
+			if err != nil {
+				return treeNode{}, err
+			}
+			node.values = append(node.values, value)
+		}
+		_, err := decoder.Token()
+		return node, err
+	}
+
+	return treeNode{}, errors.New("unexpected JSON token")
+}
@@ -0,0 +1,53 @@
+package openruntimes
+
+import (
+	"strconv"
+	"sync/atomic"
+	"unicode/utf8"
+)
+
+const LOGGER_MESSAGE_MAX_BYTES = 64 * 1024
+
+// logCounters are shared by every copy of a Logger.
+type logCounters struct {
+	lines       atomic.Int64
+	bytes       atomic.Int64
+	writeErrors atomic.Int64
+	truncated   atomic.Int64
+	suppressed  atomic.Int64
+}
+
+func (l *Logger) messageLimit() int {
+	if l.maxMessageBytes == 0 {
+		return LOGGER_MESSAGE_MAX_BYTES
+	}
+	return l.maxMessageBytes
+}
+
+// truncateMessage cuts message to the per-message cap on a UTF-8 boundary and
+// says how much was dropped. A negative MaxMessageBytes disables the cap.
+func (l *Logger) truncateMessage(message string) string {
+	limit := l.messageLimit()
+	if limit < 0 || len(message) <= limit {
+		return message
+	}
+
+	cut := limit
+	for cut > 0 && !utf8.RuneStart(message[cut]) {
+		cut--
+	}
+
+	if l.counters != nil {
+		l.counters.truncated.Add(1)
+	}
+
+	return message[:cut] + "…[truncated " + strconv.Itoa(len(message)-cut) + " bytes]"
+}
+
+// TruncatedMessages reports how many messages hit the per-message cap.
+func (l *Logger) TruncatedMessages() int64 {
+	if l.counters == nil {
+		return 0
+	}
+	return l.counters.truncated.Load()
+}
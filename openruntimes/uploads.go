@@ -0,0 +1,154 @@
+package openruntimes
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/open-runtimes/types-for-go/v4/mimetypes"
+)
+
+type UploadedFile struct {
+	FieldName   string
+	Filename    string
+	ContentType string
+	Content     []byte
+}
+
+func (f UploadedFile) Size() int {
+	return len(f.Content)
+}
+
+func (r ContextRequest) Files() ([]UploadedFile, error) {
+	mediaType, params := r.ContentType()
+
+	if !strings.HasPrefix(mediaType, "multipart/") {
+		if len(r.bodyBinary) == 0 {
+			return []UploadedFile{}, nil
+		}
+
+		return []UploadedFile{{
+			ContentType: r.Header("content-type"),
+			Content:     r.bodyBinary,
+		}}, nil
+	}
+
+	boundary := params["boundary"]
+	if boundary == "" {
+		return nil, errors.New("multipart body is missing a boundary")
+	}
+
+	files := []UploadedFile{}
+	reader := multipart.NewReader(bytes.NewReader(r.bodyBinary), boundary)
+
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.New("could not parse multipart body")
+		}
+
+		content, err := io.ReadAll(part)
+		if err != nil {
+			return nil, errors.New("could not read multipart part")
+		}
+
+		if part.FileName() == "" {
+			continue
+		}
+
+		partType := part.Header.Get("content-type")
+		if partType == "" {
+			partType = mimetypes.TypeByFilename(part.FileName())
+		}
+
+		files = append(files, UploadedFile{
+			FieldName:   part.FormName(),
+			Filename:    part.FileName(),
+			ContentType: partType,
+			Content:     content,
+		})
+	}
+
+	return files, nil
+}
+
+type UploadInspector interface {
+	Inspect(file UploadedFile) error
+}
+
+type UploadInspectorFunc func(file UploadedFile) error
+
+func (f UploadInspectorFunc) Inspect(file UploadedFile) error {
+	return f(file)
+}
+
+type UploadRejection struct {
+	Reason string
+}
+
+func (e UploadRejection) Error() string {
+	return e.Reason
+}
+
+func MaxUploadSize(bytes int) UploadInspector {
+	return UploadInspectorFunc(func(file UploadedFile) error {
+		if file.Size() > bytes {
+			return UploadRejection{Reason: "file exceeds maximum size of " + strconv.Itoa(bytes) + " bytes"}
+		}
+		return nil
+	})
+}
+
+// AllowedUploadTypes sniffs the file's magic bytes rather than trusting the declared content type.
+func AllowedUploadTypes(contentTypes ...string) UploadInspector {
+	return UploadInspectorFunc(func(file UploadedFile) error {
+		detected := http.DetectContentType(file.Content)
+		mediaType, _, _ := mime.ParseMediaType(detected)
+
+		for _, allowed := range contentTypes {
+			if mediaType == allowed {
+				return nil
+			}
+		}
+
+		return UploadRejection{Reason: "file type " + mediaType + " is not allowed"}
+	})
+}
+
+// InspectUploads runs every inspector over the files of a multipart body and
+// answers 422 with the first rejection. Other bodies, such as JSON or form
+// posts, pass through untouched.
+func InspectUploads(inspectors ...UploadInspector) Middleware {
+	return func(next Handler) Handler {
+		return func(c *Context) Response {
+			if mediaType, _ := c.Req.ContentType(); !strings.HasPrefix(mediaType, "multipart/") {
+				return next(c)
+			}
+
+			files, err := c.Req.Files()
+			if err != nil {
+				return c.Res.errorResponse(422, err.Error(), nil)
+			}
+
+			for _, file := range files {
+				for _, inspector := range inspectors {
+					if err := inspector.Inspect(file); err != nil {
+						c.Error("Upload rejected: " + file.Filename + ": " + err.Error())
+
+						return c.Res.errorResponse(422, file.Filename+": "+err.Error(), nil)
+					}
+				}
+			}
+
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package openruntimes
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime/multipart"
+	"testing"
+)
+
+func multipartBody(t *testing.T, filename string, content []byte) (string, []byte) {
+	t.Helper()
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+
+	part, err := writer.CreateFormFile("file", filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	part.Write(content)
+	writer.Close()
+
+	return writer.FormDataContentType(), body.Bytes()
+}
+
+func TestInspectUploads(t *testing.T) {
+	png := []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+	pngType, pngBody := multipartBody(t, "image.png", png)
+	textType, textBody := multipartBody(t, "notes.txt", []byte("plain text"))
+
+	tests := []struct {
+		name        string
+		contentType string
+		body        []byte
+		want        int
+		message     string
+	}{
+		{name: "allowed file", contentType: pngType, body: pngBody, want: 200},
+		{name: "rejected file", contentType: textType, body: textBody, want: 422, message: "notes.txt: file type text/plain is not allowed"},
+		{name: "json body", contentType: "application/json", body: []byte(`{"name":"widget"}`), want: 200},
+		{name: "form body", contentType: "application/x-www-form-urlencoded", body: []byte("name=widget"), want: 200},
+		{name: "missing boundary", contentType: "multipart/form-data", body: []byte("x"), want: 422, message: "multipart body is missing a boundary"},
+	}
+
+	handler := InspectUploads(AllowedUploadTypes("image/png"))(func(c *Context) Response {
+		return c.Res.Text("ok")
+	})
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := NewContext(Logger{})
+			c.Req.Headers = map[string]string{"content-type": test.contentType}
+			c.Req.SetBodyBinary(test.body)
+
+			response := handler(&c)
+			if response.StatusCode != test.want {
+				t.Fatalf("status = %d, want %d (%s)", response.StatusCode, test.want, response.Body)
+			}
+			if test.message == "" {
+				return
+			}
+
+			var body struct {
+				Errors []struct {
+					Message string `json:"message"`
+				} `json:"errors"`
+			}
+			if err := json.Unmarshal(response.Body, &body); err != nil || len(body.Errors) != 1 || body.Errors[0].Message != test.message {
+				t.Errorf("body = %s, want the error %q", response.Body, test.message)
+			}
+		})
+	}
+}
@@ -0,0 +1,123 @@
+package openruntimes
+
+import (
+	"strings"
+)
+
+type UserAgent struct {
+	Raw            string
+	Browser        string
+	BrowserVersion string
+	OS             string
+	Mobile         bool
+	Bot            bool
+	Brands         []string
+}
+
+var userAgentBots = []string{"bot", "crawler", "spider", "slurp", "curl/", "wget/", "python-requests", "go-http-client", "headless", "lighthouse", "facebookexternalhit", "preview"}
+
+var userAgentBrowsers = []struct {
+	name   string
+	marker string
+}{
+	{"Edge", "Edg/"},
+	{"Opera", "OPR/"},
+	{"Samsung Internet", "SamsungBrowser/"},
+	{"Firefox", "Firefox/"},
+	{"Chrome", "CriOS/"},
+	{"Chrome", "Chrome/"},
+	{"Safari", "Version/"},
+}
+
+var userAgentSystems = []struct {
+	name   string
+	marker string
+}{
+	{"Android", "Android"},
+	{"iOS", "iPhone"},
+	{"iOS", "iPad"},
+	{"Windows", "Windows"},
+	{"macOS", "Mac OS X"},
+	{"ChromeOS", "CrOS"},
+	{"Linux", "Linux"},
+}
+
+// UserAgent prefers Sec-CH-UA client hints when present and falls back to
+// substring hints from the User-Agent header.
+func (r ContextRequest) UserAgent() UserAgent {
+	raw := r.Header("user-agent")
+	lower := strings.ToLower(raw)
+
+	agent := UserAgent{
+		Raw:    raw,
+		Brands: parseClientHintBrands(r.Header("sec-ch-ua")),
+	}
+
+	for _, bot := range userAgentBots {
+		if strings.Contains(lower, bot) {
+			agent.Bot = true
+			break
+		}
+	}
+
+	for _, browser := range userAgentBrowsers {
+		if index := strings.Index(raw, browser.marker); index != -1 {
+			agent.Browser = browser.name
+			agent.BrowserVersion = userAgentVersion(raw[index+len(browser.marker):])
+			break
+		}
+	}
+
+	for _, system := range userAgentSystems {
+		if strings.Contains(raw, system.marker) {
+			agent.OS = system.name
+			break
+		}
+	}
+
+	agent.Mobile = strings.Contains(raw, "Mobi") || (agent.OS == "Android" && !strings.Contains(raw, "Tablet"))
+
+	if platform := strings.Trim(r.Header("sec-ch-ua-platform"), "\""); platform != "" {
+		agent.OS = platform
+	}
+
+	switch r.Header("sec-ch-ua-mobile") {
+	case "?1":
+		agent.Mobile = true
+	case "?0":
+		agent.Mobile = false
+	}
+
+	for _, brand := range agent.Brands {
+		if brand == "HeadlessChrome" {
+			agent.Bot = true
+		}
+	}
+
+	return agent
+}
+
+func userAgentVersion(value string) string {
+	end := strings.IndexAny(value, " ;)")
+	if end == -1 {
+		return value
+	}
+	return value[:end]
+}
+
+func parseClientHintBrands(header string) []string {
+	brands := []string{}
+
+	for _, entry := range strings.Split(header, ",") {
+		brand, _, _ := strings.Cut(entry, ";")
+		brand = strings.Trim(strings.TrimSpace(brand), "\"")
+
+		if brand == "" || (strings.Contains(brand, "Not") && strings.Contains(brand, "Brand")) {
+			continue
+		}
+
+		brands = append(brands, brand)
+	}
+
+	return brands
+}
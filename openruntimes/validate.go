@@ -0,0 +1,216 @@
+package openruntimes
+
+import (
+	"net/mail"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// Validator checks a bound value. Bind uses the registered one, TagValidator
+// unless RegisterValidator replaced it.
+type Validator interface {
+	Validate(v any) error
+}
+
+var (
+	validatorMutex sync.RWMutex
+	validator      Validator = TagValidator{}
+)
+
+func RegisterValidator(v Validator) {
+	validatorMutex.Lock()
+	defer validatorMutex.Unlock()
+
+	validator = v
+}
+
+func currentValidator() Validator {
+	validatorMutex.RLock()
+	defer validatorMutex.RUnlock()
+
+	return validator
+}
+
+// ValidationErrors lists every field that failed validation.
+type ValidationErrors []FieldError
+
+func (e ValidationErrors) Error() string {
+	messages := []string{}
+	for _, fieldError := range e {
+		messages = append(messages, fieldError.Error())
+	}
+	return "validation failed: " + strings.Join(messages, "; ")
+}
+
+// TagValidator applies `validate:"..."` tags: required, email, url, min=N,
+// max=N, len=N and oneof=a b c. Sizes are lengths for strings, slices and
+// maps, and values for numbers. Nested structs are validated too, with
+// fields named by their json tag when there is one.
+type TagValidator struct{}
+
+func (TagValidator) Validate(v any) error {
+	value := reflect.ValueOf(v)
+	for value.Kind() == reflect.Pointer {
+		if value.IsNil() {
+			return nil
+		}
+		value = value.Elem()
+	}
+
+	if value.Kind() != reflect.Struct {
+		return nil
+	}
+
+	problems := ValidationErrors{}
+	validateStruct(value, "", &problems)
+
+	if len(problems) == 0 {
+		return nil
+	}
+	return problems
+}
+
+func fieldName(field reflect.StructField) string {
+	for _, tag := range []string{"json", "query", "header", "param", "form"} {
+		if name, _, _ := strings.Cut(field.Tag.Get(tag), ","); name != "" && name != "-" {
+			return name
+		}
+	}
+	return field.Name
+}
+
+func validateStruct(value reflect.Value, prefix string, problems *ValidationErrors) {
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name := prefix + fieldName(field)
+		fieldValue := value.Field(i)
+
+		for _, rule := range strings.Split(field.Tag.Get("validate"), ",") {
+			if rule = strings.TrimSpace(rule); rule == "" {
+				continue
+			}
+
+			if message := checkRule(fieldValue, rule); message != "" {
+				*problems = append(*problems, FieldError{Field: name, Message: message})
+				break
+			}
+		}
+
+		nested := fieldValue
+		if nested.Kind() == reflect.Pointer && !nested.IsNil() {
+			nested = nested.Elem()
+		}
+		if nested.Kind() == reflect.Struct && nested.Type() != timeType {
+			nestedPrefix := name + "."
+			if field.Anonymous {
+				nestedPrefix = prefix
+			}
+			validateStruct(nested, nestedPrefix, problems)
+		}
+	}
+}
+
+// checkRule returns why value breaks rule, or "" when it does not. Rules
+// other than required pass on empty strings, slices and maps and on nil
+// pointers, so optional fields can be constrained when present; numbers are
+// always checked, so min=1 rejects 0.
+func checkRule(value reflect.Value, rule string) string {
+	name, argument, _ := strings.Cut(rule, "=")
+
+	switch name {
+	case "required", "email", "url", "min", "max", "len", "oneof":
+	default:
+		return "has unknown validation rule " + strconv.Quote(name)
+	}
+
+	if name == "required" {
+		if value.IsZero() {
+			return "is required"
+		}
+		return ""
+	}
+
+	if value.Kind() == reflect.Pointer {
+		if value.IsNil() {
+			return ""
+		}
+		value = value.Elem()
+	}
+
+	switch value.Kind() {
+	case reflect.String, reflect.Slice, reflect.Map:
+		if value.Len() == 0 {
+			return ""
+		}
+	}
+
+	switch name {
+	case "email":
+		address, err := mail.ParseAddress(value.String())
+		if err != nil || address.Address != value.String() {
+			return "must be a valid email address"
+		}
+	case "url":
+		parsed, err := url.Parse(value.String())
+		if err != nil || parsed.Scheme == "" || parsed.Host == "" {
+			return "must be a valid URL"
+		}
+	case "min", "max", "len":
+		limit, err := strconv.ParseFloat(argument, 64)
+		if err != nil {
+			return "has an invalid " + name + " rule"
+		}
+
+		size, unit := measure(value)
+		switch {
+		case name == "min" && size < limit:
+			return describeLimit("at least", argument, unit)
+		case name == "max" && size > limit:
+			return describeLimit("at most", argument, unit)
+		case name == "len" && size != limit:
+			return describeLimit("exactly", argument, unit)
+		}
+	case "oneof":
+		text := formatFieldValue(value.Interface())
+		for _, option := range strings.Fields(argument) {
+			if option == text {
+				return ""
+			}
+		}
+		return "must be one of " + strings.Join(strings.Fields(argument), ", ")
+	}
+
+	return ""
+}
+
+// measure returns the size rules compare against, and its unit when it is a length.
+func measure(value reflect.Value) (float64, string) {
+	switch value.Kind() {
+	case reflect.String:
+		return float64(utf8.RuneCountInString(value.String())), "characters"
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return float64(value.Len()), "items"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(value.Int()), ""
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(value.Uint()), ""
+	case reflect.Float32, reflect.Float64:
+		return value.Float(), ""
+	}
+	return 0, ""
+}
+
+func describeLimit(comparison string, argument string, unit string) string {
+	if unit != "" {
+		return "must have " + comparison + " " + argument + " " + unit
+	}
+	return "must be " + comparison + " " + argument
+}
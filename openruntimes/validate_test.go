@@ -0,0 +1,136 @@
+package openruntimes
+
+import (
+	"errors"
+	"testing"
+)
+
+func validationFields(t *testing.T, err error) map[string]string {
+	t.Helper()
+
+	fields := map[string]string{}
+	if err == nil {
+		return fields
+	}
+
+	var problems ValidationErrors
+	if !errors.As(err, &problems) {
+		t.Fatalf("error = %v (%T), want ValidationErrors", err, err)
+	}
+	for _, problem := range problems {
+		fields[problem.Field] = problem.Message
+	}
+	return fields
+}
+
+func TestTagValidator(t *testing.T) {
+	type address struct {
+		City string `json:"city" validate:"required"`
+	}
+	type signup struct {
+		Name    string   `json:"name" validate:"required,min=3"`
+		Email   string   `json:"email" validate:"email"`
+		Website string   `json:"website" validate:"url"`
+		Age     int      `json:"age" validate:"min=18,max=130"`
+		Qty     int      `json:"qty" validate:"min=1"`
+		Level   int      `json:"level" validate:"oneof=1 2 3"`
+		Role    string   `json:"role" validate:"oneof=admin user"`
+		Tags    []string `json:"tags" validate:"max=2"`
+		Code    string   `json:"code" validate:"len=4"`
+		Nick    *string  `json:"nick" validate:"min=2"`
+		Address address  `json:"address"`
+	}
+
+	tests := []struct {
+		name  string
+		value signup
+		want  map[string]string
+	}{
+		{
+			name: "valid",
+			value: signup{
+				Name: "ana", Email: "ana@example.com", Website: "https://example.com",
+				Age: 30, Qty: 1, Level: 2, Role: "admin", Tags: []string{"a"}, Code: "abcd",
+				Address: address{City: "Zagreb"},
+			},
+			want: map[string]string{},
+		},
+		{
+			name: "every rule broken",
+			value: signup{
+				Name: "an", Email: "ana@", Website: "example.com", Age: 12, Qty: 0, Level: 0,
+				Role: "root", Tags: []string{"a", "b", "c"}, Code: "abc",
+			},
+			want: map[string]string{
+				"name":         "must have at least 3 characters",
+				"email":        "must be a valid email address",
+				"website":      "must be a valid URL",
+				"age":          "must be at least 18",
+				"qty":          "must be at least 1",
+				"level":        "must be one of 1, 2, 3",
+				"role":         "must be one of admin, user",
+				"tags":         "must have at most 2 items",
+				"code":         "must have exactly 4 characters",
+				"address.city": "is required",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := validationFields(t, TagValidator{}.Validate(&test.value))
+
+			for field, message := range test.want {
+				if got[field] != message {
+					t.Errorf("%s: got %q, want %q", field, got[field], message)
+				}
+			}
+			for field, message := range got {
+				if _, ok := test.want[field]; !ok {
+					t.Errorf("%s: unexpected %q", field, message)
+				}
+			}
+		})
+	}
+}
+
+func TestTagValidatorSkipsEmptyOptionalValues(t *testing.T) {
+	type optional struct {
+		Email string   `validate:"email"`
+		Tags  []string `validate:"min=1"`
+		Nick  *string  `validate:"min=2"`
+	}
+
+	if err := (TagValidator{}).Validate(optional{}); err != nil {
+		t.Fatalf("Validate() error = %v, want nil", err)
+	}
+}
+
+func TestTagValidatorReportsUnknownRules(t *testing.T) {
+	type typo struct {
+		Name string `validate:"requird"`
+	}
+
+	got := validationFields(t, TagValidator{}.Validate(typo{}))
+	if got["Name"] != `has unknown validation rule "requird"` {
+		t.Fatalf("Validate() = %v, want the unknown rule reported", got)
+	}
+}
+
+type rejectAll struct{}
+
+func (rejectAll) Validate(any) error {
+	return ValidationErrors{{Field: "all", Message: "rejected"}}
+}
+
+func TestBindUsesRegisteredValidator(t *testing.T) {
+	RegisterValidator(rejectAll{})
+	defer RegisterValidator(TagValidator{})
+
+	req := newBindRequest("application/json", `{"name":"widget"}`)
+
+	got := validationFields(t, req.Bind(&bindOrder{}))
+	if got["all"] != "rejected" {
+		t.Fatalf("Bind() = %v, want the registered validator's errors", got)
+	}
+}
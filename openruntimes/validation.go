@@ -0,0 +1,90 @@
+package openruntimes
+
+import (
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type ResponseValidationError struct {
+	Problems []string
+}
+
+func (e ResponseValidationError) Error() string {
+	return "invalid response: " + strings.Join(e.Problems, "; ")
+}
+
+// Validate checks the response the way strict mode does. A StatusCode of 0
+// counts as 200, as it does when the response is sent.
+func (r Response) Validate() error {
+	if r.StatusCode == 0 {
+		r.StatusCode = 200
+	}
+
+	problems := []string{}
+
+	if r.StatusCode < 100 || r.StatusCode > 599 {
+		problems = append(problems, "status code "+strconv.Itoa(r.StatusCode)+" is outside 100-599")
+	}
+
+	names := []string{}
+	for name := range r.AllHeaders() {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if !isHeaderToken(name) {
+			problems = append(problems, "header name "+strconv.Quote(name)+" is invalid")
+		}
+
+		for _, value := range r.Values(name) {
+			if strings.ContainsAny(value, "\r\n\x00") {
+				problems = append(problems, "header "+name+" contains CR, LF or NUL")
+			}
+		}
+	}
+
+	for name, value := range r.Trailers {
+		if !isHeaderToken(name) || strings.ContainsAny(value, "\r\n\x00") {
+			problems = append(problems, "trailer "+strconv.Quote(name)+" is invalid")
+		}
+	}
+
+	if (r.StatusCode == 204 || r.StatusCode == 304) && (len(r.Body) > 0 || r.BodyReader != nil) {
+		problems = append(problems, "status code "+strconv.Itoa(r.StatusCode)+" must not have a body")
+	}
+
+	switch r.StatusCode {
+	case 301, 302, 303, 307, 308:
+		if len(r.Values("location")) == 0 {
+			problems = append(problems, "status code "+strconv.Itoa(r.StatusCode)+" requires a location header")
+		}
+	}
+
+	if len(problems) > 0 {
+		return ResponseValidationError{Problems: problems}
+	}
+
+	return nil
+}
+
+func isHeaderToken(name string) bool {
+	if name == "" {
+		return false
+	}
+
+	for _, c := range []byte(name) {
+		if c <= 0x20 || c >= 0x7f || strings.IndexByte("\"(),/:;<=>?@[\\]{}", c) != -1 {
+			return false
+		}
+	}
+
+	return true
+}
+
+func IsStrictMode() bool {
+	status := os.Getenv("OPEN_RUNTIMES_STRICT_RESPONSES")
+	return status == "enabled" || status == "true"
+}
@@ -0,0 +1,37 @@
+package openruntimes
+
+import "testing"
+
+func TestResponseValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		response Response
+		valid    bool
+	}{
+		{name: "zero status is 200", response: Response{Body: []byte("ok")}, valid: true},
+		{name: "out of range status", response: Response{StatusCode: 42}, valid: false},
+		{name: "body on 204", response: Response{StatusCode: 204, Body: []byte("x")}, valid: false},
+		{name: "header injection", response: Response{StatusCode: 200, Headers: map[string]string{"x-a": "1\r\nx-b: 2"}}, valid: false},
+		{name: "redirect without location", response: Response{StatusCode: 302}, valid: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if err := test.response.Validate(); (err == nil) != test.valid {
+				t.Errorf("Validate() error = %v, want valid %v", err, test.valid)
+			}
+		})
+	}
+}
+
+func TestStrictResponsesAcceptZeroStatus(t *testing.T) {
+	server := NewServer(func(c *Context) Response {
+		return Response{Body: []byte("ok")}
+	})
+	server.StrictResponses = true
+
+	response, _ := server.Invoke(ContextRequest{Method: "GET", Path: "/", Url: "/"})
+	if response.StatusCode == 500 {
+		t.Fatal("strict mode rejected a response with StatusCode 0")
+	}
+}
@@ -0,0 +1,70 @@
+package openruntimes
+
+import (
+	"mime"
+	"sort"
+	"strings"
+)
+
+const HEADER_API_VERSION = "x-api-version"
+
+// Versioned selects a handler variant from the x-api-version header or the
+// "version"/"profile" parameters of the Accept header, falling back to
+// defaultVersion. The chosen version is echoed in the x-api-version header.
+func Versioned(defaultVersion string, variants map[string]Handler) Handler {
+	return func(c *Context) Response {
+		version := RequestedVersion(c.Req, variants)
+		if version == "" {
+			version = defaultVersion
+		}
+
+		handler, ok := variants[version]
+		if !ok {
+			supported := []string{}
+			for key := range variants {
+				supported = append(supported, key)
+			}
+			sort.Strings(supported)
+
+			return c.Res.Json(errorBody("Unsupported API version. Supported versions: "+strings.Join(supported, ", ")), c.Res.WithStatusCode(406))
+		}
+
+		response := handler(c)
+
+		response.Headers = cloneStringMap(response.Headers)
+		if response.Headers == nil {
+			response.Headers = map[string]string{}
+		}
+		headerSet(response.Headers, HEADER_API_VERSION, version)
+
+		return response
+	}
+}
+
+func RequestedVersion(req ContextRequest, variants map[string]Handler) string {
+	if version := strings.TrimSpace(req.Header(HEADER_API_VERSION)); version != "" {
+		return version
+	}
+
+	for _, accept := range strings.Split(req.Header("accept"), ",") {
+		_, params, err := mime.ParseMediaType(strings.TrimSpace(accept))
+		if err != nil {
+			continue
+		}
+
+		if version := params["version"]; version != "" {
+			return version
+		}
+
+		if profile := params["profile"]; profile != "" {
+			if _, ok := variants[profile]; ok {
+				return profile
+			}
+
+			segment := profile[strings.LastIndex(strings.TrimRight(profile, "/"), "/")+1:]
+			return strings.TrimRight(segment, "/")
+		}
+	}
+
+	return ""
+}
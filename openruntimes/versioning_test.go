@@ -0,0 +1,54 @@
+package openruntimes
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestVersioned(t *testing.T) {
+	shared := map[string]string{"content-type": "text/plain"}
+	handler := Versioned("1", map[string]Handler{
+		"1": func(c *Context) Response { return Response{Body: []byte("v1"), StatusCode: 200, Headers: shared} },
+		"2": func(c *Context) Response { return c.Res.Text("v2") },
+	})
+
+	tests := []struct {
+		name    string
+		headers map[string]string
+		want    string
+		status  int
+	}{
+		{name: "default", headers: map[string]string{}, want: "1", status: 200},
+		{name: "header", headers: map[string]string{HEADER_API_VERSION: "2"}, want: "2", status: 200},
+		{name: "accept parameter", headers: map[string]string{"accept": "application/json; version=2"}, want: "2", status: 200},
+		{name: "unsupported", headers: map[string]string{HEADER_API_VERSION: "3"}, status: 406},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := NewContext(Logger{})
+			c.Req.Headers = test.headers
+
+			response := handler(&c)
+			if response.StatusCode != test.status {
+				t.Fatalf("status = %d, want %d", response.StatusCode, test.status)
+			}
+
+			if test.status == 406 {
+				var body map[string]any
+				if err := json.Unmarshal(response.Body, &body); err != nil || body["errors"] == nil {
+					t.Errorf("body = %s, want an errorBody", response.Body)
+				}
+				return
+			}
+
+			if version, _ := headerGet(response.Headers, HEADER_API_VERSION); version != test.want {
+				t.Errorf("version = %q, want %q", version, test.want)
+			}
+		})
+	}
+
+	if _, ok := headerGet(shared, HEADER_API_VERSION); ok {
+		t.Errorf("handler's headers = %v, want them left untouched", shared)
+	}
+}
@@ -0,0 +1,221 @@
+package openruntimes
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+type YamlCodec struct{}
+
+func (YamlCodec) Marshal(v any) ([]byte, error) {
+	return MarshalYaml(v)
+}
+
+func (YamlCodec) Unmarshal(data []byte, v any) error {
+	return errors.New("decoding YAML is not supported")
+}
+
+func MarshalYaml(v any) ([]byte, error) {
+	tree, err := newTree(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var buffer bytes.Buffer
+	writeYamlRoot(&buffer, tree)
+
+	return buffer.Bytes(), nil
+}
+
+func writeYamlRoot(buffer *bytes.Buffer, node treeNode) {
+	switch {
+	case node.kind == treeMap && len(node.keys) > 0:
+		writeYamlMap(buffer, node, 0)
+	case node.kind == treeList && len(node.values) > 0:
+		writeYamlList(buffer, node, 0)
+	default:
+		buffer.WriteString(yamlInline(node, 0))
+		buffer.WriteString("\n")
+	}
+}
+
+func writeYamlMap(buffer *bytes.Buffer, node treeNode, indent int) {
+	for i, key := range node.keys {
+		if i > 0 {
+			buffer.WriteString(strings.Repeat(" ", indent))
+		}
+		buffer.WriteString(yamlString(key, indent))
+		buffer.WriteString(":")
+		writeYamlValue(buffer, node.values[i], indent)
+	}
+}
+
+func writeYamlList(buffer *bytes.Buffer, node treeNode, indent int) {
+	for i, value := range node.values {
+		if i > 0 {
+			buffer.WriteString(strings.Repeat(" ", indent))
+		}
+		buffer.WriteString("- ")
+
+		switch {
+		case value.kind == treeMap && len(value.keys) > 0:
+			writeYamlMap(buffer, value, indent+2)
+		case value.kind == treeList && len(value.values) > 0:
+			writeYamlList(buffer, value, indent+2)
+		default:
+			buffer.WriteString(yamlInline(value, indent+2))
+			buffer.WriteString("\n")
+		}
+	}
+}
+
+// writeYamlValue continues a "key:" line with either an inline value or a nested block.
+func writeYamlValue(buffer *bytes.Buffer, node treeNode, indent int) {
+	switch {
+	case node.kind == treeMap && len(node.keys) > 0:
+		buffer.WriteString("\n")
+		buffer.WriteString(strings.Repeat(" ", indent+2))
+		writeYamlMap(buffer, node, indent+2)
+	case node.kind == treeList && len(node.values) > 0:
+		buffer.WriteString("\n")
+		buffer.WriteString(strings.Repeat(" ", indent+2))
+		writeYamlList(buffer, node, indent+2)
+	default:
+		buffer.WriteString(" ")
+		buffer.WriteString(yamlInline(node, indent))
+		buffer.WriteString("\n")
+	}
+}
+
+func yamlInline(node treeNode, indent int) string {
+	switch node.kind {
+	case treeMap:
+		return "{}"
+	case treeList:
+		return "[]"
+	}
+
+	switch value := node.value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		if value {
+			return "true"
+		}
+		return "false"
+	case json.Number:
+		return value.String()
+	case string:
+		return yamlString(value, indent)
+	}
+
+	return "null"
+}
+
+var yamlReserved = map[string]bool{
+	"true": true, "false": true, "yes": true, "no": true, "on": true, "off": true,
+	"y": true, "n": true, "null": true, "~": true,
+}
+
+func yamlString(value string, indent int) string {
+	if isYamlBlockCandidate(value) {
+		chomping := "|-"
+		if strings.HasSuffix(value, "\n") {
+			chomping = "|"
+			value = strings.TrimSuffix(value, "\n")
+		}
+
+		padding := strings.Repeat(" ", indent+2)
+		lines := strings.Split(value, "\n")
+		for i, line := range lines {
+			if line != "" {
+				lines[i] = padding + line
+			}
+		}
+
+		return chomping + "\n" + strings.Join(lines, "\n")
+	}
+
+	if needsYamlQuotes(value) {
+		var buffer bytes.Buffer
+		encoder := json.NewEncoder(&buffer)
+		encoder.SetEscapeHTML(false)
+		encoder.Encode(value)
+		return strings.TrimSuffix(buffer.String(), "\n")
+	}
+
+	return value
+}
+
+func isYamlBlockCandidate(value string) bool {
+	if !strings.Contains(value, "\n") || strings.ContainsAny(value, "\r\t") {
+		return false
+	}
+
+	if strings.HasPrefix(value, " ") || strings.HasSuffix(value, "\n\n") {
+		return false
+	}
+
+	for _, line := range strings.Split(value, "\n") {
+		if strings.HasSuffix(line, " ") {
+			return false
+		}
+	}
+
+	return true
+}
+
+func needsYamlQuotes(value string) bool {
+	if value == "" || yamlReserved[strings.ToLower(value)] {
+		return true
+	}
+
+	if strings.TrimSpace(value) != value {
+		return true
+	}
+
+	if strings.ContainsAny(value[:1], "-?:,[]{}#&*!|>'\"%@`") {
+		return true
+	}
+
+	if strings.Contains(value, ": ") || strings.Contains(value, " #") || strings.HasSuffix(value, ":") {
+		return true
+	}
+
+	for _, c := range value {
+		if c < 0x20 || c == 0x7f {
+			return true
+		}
+	}
+
+	if _, err := json.Number(value).Float64(); err == nil {
+		return true
+	}
+
+	return looksLikeYamlNumber(value)
+}
+
+func looksLikeYamlNumber(value string) bool {
+	lower := strings.ToLower(value)
+	if lower == ".inf" || lower == "-.inf" || lower == "+.inf" || lower == ".nan" {
+		return true
+	}
+
+	return strings.HasPrefix(lower, "0x") || strings.HasPrefix(lower, "0o")
+}
+
+func (r ContextResponse) Yaml(v any, optionalSetters ...ResponseOption) Response {
+	body, err := MarshalYaml(v)
+	if err != nil {
+		optionalSetters = append(optionalSetters, r.WithStatusCode(500))
+		return r.Text("Error encoding YAML.", optionalSetters...)
+	}
+
+	optionalSetters = append(optionalSetters, r.WithHeaders(map[string]string{
+		"content-type": "application/yaml",
+	}))
+
+	return r.Binary(body, optionalSetters...)
+}